@@ -0,0 +1,100 @@
+// Command gorules-export writes Terraform configuration, with import blocks,
+// for the projects, groups and environments of an existing GoRules BRMS.
+//
+//	GORULES_TOKEN=... gorules-export -base-url https://brms.example.com -out ./brms
+//	terraform -chdir=brms init && terraform -chdir=brms plan
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/export"
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/provider"
+)
+
+// set during build time via ldflags, like the provider
+var version = "dev"
+
+type keyList []string
+
+func (k *keyList) String() string     { return strings.Join(*k, ",") }
+func (k *keyList) Set(v string) error { *k = append(*k, v); return nil }
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gorules-export: ")
+
+	var (
+		baseURL   = flag.String("base-url", os.Getenv("GORULES_BASE_URL"), "BRMS API root (default $GORULES_BASE_URL)")
+		tokenFile = flag.String("token-file", "", "read the API token from this file instead of $GORULES_TOKEN")
+		out       = flag.String("out", ".", "directory for the generated .tf files")
+		force     = flag.Bool("force", false, "overwrite existing files")
+		timeout   = flag.Duration("timeout", 30*time.Second, "timeout of each API request")
+		projects  keyList
+	)
+	flag.Var(&projects, "project", "only export the project with this key (repeatable; default: all projects)")
+	flag.Parse()
+
+	if *baseURL == "" {
+		log.Fatal("-base-url or GORULES_BASE_URL is required")
+	}
+	token := os.Getenv("GORULES_TOKEN")
+	if *tokenFile != "" {
+		raw, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		token = strings.TrimSpace(string(raw))
+	}
+	if token == "" {
+		log.Fatal("set GORULES_TOKEN or -token-file")
+	}
+
+	cfg := provider.NewClientConfig(*baseURL, token, "gorules-export/"+version, *timeout)
+	inv, err := provider.ReadInventory(context.Background(), cfg, projects...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files := export.Files(inv, cfg.BaseURL)
+	if err := write(*out, files, *force); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d projects to %s; run terraform plan there to review the imports\n", len(inv.Projects), *out)
+}
+
+// write creates dir and the files in it, without overwriting unless force is set
+func write(dir string, files map[string][]byte, force bool) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !force {
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(dir, name)); !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("%s already exists (use -force to overwrite)", filepath.Join(dir, name))
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), files[name], 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
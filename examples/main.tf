@@ -1,8 +1,8 @@
-# Ejemplo de configuración para usar el GoRules Terraform Provider
+# Example configuration for the GoRules Terraform Provider
 
 terraform {
   required_version = ">= 1.0"
-  
+
   required_providers {
     gorules = {
       source  = "andredelgado-ruiz/gorules"
@@ -11,50 +11,50 @@ terraform {
   }
 }
 
-# Configuración del provider GoRules
+# GoRules provider configuration
 provider "gorules" {
-  # URL base de tu instancia de GoRules
-  base_url = "https://tu-instancia.gorules.io"
-  
-  # Token de acceso personal (PAT)
-  # Es recomendable usar variables de entorno: TF_VAR_gorules_token
+  # Base URL of your GoRules instance
+  base_url = "https://your-instance.gorules.io"
+
+  # Personal Access Token (PAT)
+  # Prefer environment variables: TF_VAR_gorules_token
   token = var.gorules_token
 }
 
-# Variable para el token (define esto en terraform.tfvars o como variable de entorno)
+# Token variable (set it in terraform.tfvars or as an environment variable)
 variable "gorules_token" {
-  description = "Personal Access Token para GoRules API"
+  description = "Personal Access Token for the GoRules API"
   type        = string
   sensitive   = true
 }
 
-# Ejemplo: Crear un proyecto
+# Example: create a project
 resource "gorules_project" "example" {
-  name = "Mi Proyecto de Prueba"
-  key  = "mi-proyecto-prueba"  # Debe seguir el patrón: ^[a-z0-9]{2,}(-[a-z0-9]+)*$
+  name = "My Test Project"
+  key  = "my-test-project"  # Must match: ^[a-z0-9]{2,}(-[a-z0-9]+)*$
 }
 
-# Ejemplo: Crear un entorno en el proyecto
+# Example: create an environment in the project
 resource "gorules_environment" "development" {
   project_id = gorules_project.example.id
   name       = "development"
-  type       = "development"  # Requerido según el schema
+  type       = "development"  # Required by the schema
 }
 
-# Ejemplo: Crear un grupo
+# Example: create a group
 resource "gorules_group" "developers" {
   name        = "Developers"
   project_id  = gorules_project.example.id
-  permissions = ["read", "write"]  # Lista de permisos
+  permissions = ["read", "write"]  # List of permissions
 }
 
-# Outputs útiles
+# Useful outputs
 output "project_id" {
-  description = "ID del proyecto creado"
+  description = "ID of the created project"
   value       = gorules_project.example.id
 }
 
 output "environment_id" {
-  description = "ID del entorno creado"
+  description = "ID of the created environment"
   value       = gorules_environment.development.id
-}
\ No newline at end of file
+}
@@ -0,0 +1,172 @@
+// Package export renders a GoRules inventory as Terraform configuration:
+// one file per project with its resources and import blocks, plus a
+// providers.tf. Objects reference each other through resource addresses
+// (project_id = gorules_project.x.id, approval_groups = [gorules_group.y.name])
+// so Terraform orders the creates and keeps the links on rename.
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/provider"
+)
+
+const ProvidersFile = "providers.tf"
+
+// Files returns the generated configuration by file name. baseURL is written
+// to the provider block; the token is left to a sensitive variable.
+func Files(inv *provider.Inventory, baseURL string) map[string][]byte {
+	g := &generator{used: map[string]bool{"gorules_project.providers": true}} // providers.tf is taken
+	out := map[string][]byte{ProvidersFile: providers(baseURL)}
+	for _, p := range inv.Projects {
+		label := g.label("gorules_project", p.Key)
+		out[label+".tf"] = g.project(label, p)
+	}
+	return out
+}
+
+type generator struct {
+	used map[string]bool // "<type>.<label>"
+}
+
+// label returns a unique resource name for typ derived from name
+func (g *generator) label(typ, name string) string {
+	base := provider.ResourceLabel(name)
+	label := base
+	for n := 2; g.used[typ+"."+label]; n++ {
+		label = base + "_" + strconv.Itoa(n)
+	}
+	g.used[typ+"."+label] = true
+	return label
+}
+
+func providers(baseURL string) []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	tf := body.AppendNewBlock("terraform", nil).Body()
+	rp := tf.AppendNewBlock("required_providers", nil).Body()
+	rp.SetAttributeRaw("gorules", hclwrite.TokensForObject([]hclwrite.ObjectAttrTokens{
+		{Name: hclwrite.TokensForIdentifier("source"), Value: hclwrite.TokensForValue(cty.StringVal("andredelgado-ruiz/gorules"))},
+	}))
+	body.AppendNewline()
+
+	v := body.AppendNewBlock("variable", []string{"gorules_token"}).Body()
+	v.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+	v.SetAttributeValue("sensitive", cty.True)
+	body.AppendNewline()
+
+	p := body.AppendNewBlock("provider", []string{"gorules"}).Body()
+	p.SetAttributeValue("base_url", cty.StringVal(baseURL))
+	p.SetAttributeTraversal("token", traversal("var", "gorules_token"))
+	return f.Bytes()
+}
+
+func (g *generator) project(label string, p provider.InventoryProject) []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	projectID := traversal("gorules_project", label, "id")
+
+	b := resource(body, "gorules_project", label, p.ID)
+	b.SetAttributeValue("name", cty.StringVal(p.Name))
+	b.SetAttributeValue("key", cty.StringVal(p.Key))
+	if p.Protected != nil {
+		b.SetAttributeValue("protected", cty.BoolVal(*p.Protected))
+	}
+
+	groups := map[string]groupRef{}
+	for _, grp := range p.Groups {
+		gl := g.label("gorules_group", p.Key+"_"+grp.Name)
+		groups[grp.ID] = groupRef{grp.Name, traversal("gorules_group", gl, "name")}
+
+		b := resource(body, "gorules_group", gl, p.ID+"/"+grp.ID)
+		b.SetAttributeTraversal("project_id", projectID)
+		b.SetAttributeValue("name", cty.StringVal(grp.Name))
+		if grp.Description != "" {
+			b.SetAttributeValue("description", cty.StringVal(grp.Description))
+		}
+		b.SetAttributeValue("permissions", stringList(grp.Permissions))
+	}
+
+	for _, env := range p.Environments {
+		el := g.label("gorules_environment", p.Key+"_"+env.Key)
+
+		b := resource(body, "gorules_environment", el, p.ID+"/"+env.ID)
+		b.SetAttributeTraversal("project_id", projectID)
+		b.SetAttributeValue("name", cty.StringVal(env.Name))
+		b.SetAttributeValue("key", cty.StringVal(env.Key))
+		b.SetAttributeValue("type", cty.StringVal(env.Type))
+		if env.ApprovalMode != nil {
+			b.SetAttributeValue("approval_mode", cty.StringVal(*env.ApprovalMode))
+		}
+		if len(env.ApprovalGroupIDs) > 0 {
+			var approvers []groupRef
+			for _, id := range env.ApprovalGroupIDs {
+				ref, ok := groups[id]
+				if !ok {
+					// the provider would drop it too: keep the ID visible for review
+					b.AppendUnstructuredTokens(comment(fmt.Sprintf("approval group %s is not in project %q", id, p.Key)))
+					continue
+				}
+				approvers = append(approvers, ref)
+			}
+			// sorted by name, like the provider stores them
+			sort.Slice(approvers, func(i, j int) bool { return approvers[i].name < approvers[j].name })
+			refs := make([]hclwrite.Tokens, 0, len(approvers))
+			for _, ref := range approvers {
+				refs = append(refs, hclwrite.TokensForTraversal(ref.address))
+			}
+			b.SetAttributeRaw("approval_groups", hclwrite.TokensForTuple(refs))
+		}
+	}
+	return hclwrite.Format(f.Bytes())
+}
+
+type groupRef struct {
+	name    string
+	address hcl.Traversal // gorules_group.x.name
+}
+
+// resource appends a resource block and its import block, and returns the
+// resource body
+func resource(body *hclwrite.Body, typ, label, importID string) *hclwrite.Body {
+	if len(body.Blocks()) > 0 {
+		body.AppendNewline()
+	}
+	b := body.AppendNewBlock("resource", []string{typ, label}).Body()
+	body.AppendNewline()
+	imp := body.AppendNewBlock("import", nil).Body()
+	imp.SetAttributeTraversal("to", traversal(typ, label))
+	imp.SetAttributeValue("id", cty.StringVal(importID))
+	return b
+}
+
+func traversal(root string, attrs ...string) hcl.Traversal {
+	t := hcl.Traversal{hcl.TraverseRoot{Name: root}}
+	for _, a := range attrs {
+		t = append(t, hcl.TraverseAttr{Name: a})
+	}
+	return t
+}
+
+func stringList(xs []string) cty.Value {
+	if len(xs) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	vals := make([]cty.Value, len(xs))
+	for i, x := range xs {
+		vals[i] = cty.StringVal(x)
+	}
+	return cty.ListVal(vals)
+}
+
+func comment(text string) hclwrite.Tokens {
+	return hclwrite.Tokens{{Type: hclsyntax.TokenComment, Bytes: []byte("# " + text + "\n")}}
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/provider"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/*.golden files")
+
+func TestFiles(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	pricing := srv.AddProject(mockserver.Project{Name: "Tarificación Europa", Key: "tarificacion-europa", Protected: true})
+	approvers := srv.AddGroup(mockserver.Group{ProjectID: pricing.ID, Name: "Approvers", Permissions: []string{"deploy", "approve"}})
+	admins := srv.AddGroup(mockserver.Group{ProjectID: pricing.ID, Name: "admins"})
+	description := `Reviews ${var} "quoted"`
+	srv.AddGroup(mockserver.Group{ProjectID: pricing.ID, Name: "approvers!", Description: &description, Permissions: []string{}})
+	mode := "require_one_per_team"
+	srv.AddEnvironment(mockserver.Environment{ProjectID: pricing.ID, Name: "Production", Key: "production", Type: "brms",
+		ApprovalMode: &mode, ApprovalGroups: []string{approvers.ID, admins.ID}})
+	srv.AddEnvironment(mockserver.Environment{ProjectID: pricing.ID, Name: "Staging", Key: "staging", Type: "deployment"})
+	// a project keyed like the providers file must not overwrite it
+	srv.AddProject(mockserver.Project{Name: "Providers", Key: "providers"})
+
+	cfg := provider.NewClientConfig(srv.URL+"/", srv.Token, "gorules-export/test", 5*time.Second)
+	inv, err := provider.ReadInventory(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := Files(inv, "https://brms.example.com")
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if got := len(names); got != 3 {
+		t.Fatalf("got files %v", names)
+	}
+	for _, name := range names {
+		golden := filepath.Join("testdata", name+".golden")
+		if *updateGolden {
+			if err := os.WriteFile(golden, files[name], 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%v (run go test -update to create it)", err)
+		}
+		if string(files[name]) != string(want) {
+			t.Errorf("%s mismatch\n got: %s\nwant: %s", golden, files[name], want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package jdm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Diff compares an expected decision output with the actual one and returns
+// one line per mismatch. Objects are matched as a subset: keys missing from
+// expected are ignored, so test cases only pin the fields they care about.
+// Arrays must match element by element.
+func Diff(expected, actual any) []string {
+	var out []string
+	diffAt("", expected, actual, &out)
+	return out
+}
+
+func diffAt(path string, expected, actual any, out *[]string) {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			*out = append(*out, mismatch(path, expected, actual))
+			return
+		}
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			av, found := a[k]
+			if !found {
+				*out = append(*out, fmt.Sprintf("%s: expected %s, got <missing>", joinPath(path, k), render(e[k])))
+				continue
+			}
+			diffAt(joinPath(path, k), e[k], av, out)
+		}
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(e) {
+			*out = append(*out, mismatch(path, expected, actual))
+			return
+		}
+		for i := range e {
+			diffAt(fmt.Sprintf("%s[%d]", path, i), e[i], a[i], out)
+		}
+	default:
+		if !equal(expected, actual) {
+			*out = append(*out, mismatch(path, expected, actual))
+		}
+	}
+}
+
+func mismatch(path string, expected, actual any) string {
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", path, render(expected), render(actual))
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func render(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
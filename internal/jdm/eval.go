@@ -0,0 +1,359 @@
+package jdm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Local graph evaluation
+//
+// Nodes run in topological order. A node receives the merged outputs of the
+// parents that reached it; switch nodes only forward along the edges of the
+// statements that matched. The result is the merged input of every output
+// node that was reached. Function nodes (JavaScript) are not supported.
+// -----------------------------------------------------------------------------
+
+// Evaluate runs the decision graph against input (decoded JSON: objects are
+// map[string]any, numbers float64) and returns the graph output.
+func Evaluate(doc *Document, input any) (any, error) {
+	if errs := Validate(doc); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid JDM document: %s", strings.Join(errs, "; "))
+	}
+
+	order := topoSort(doc)
+	byID := map[string]Node{}
+	for _, n := range doc.Nodes {
+		byID[n.ID] = n
+	}
+
+	outputs := map[string]any{}      // node ID -> output of that node
+	handles := map[string][]string{} // switch node ID -> matched statement IDs
+	reached := map[string]bool{}
+	var result any
+
+	for _, id := range order {
+		n := byID[id]
+
+		var in any
+		if n.Type == NodeTypeInput {
+			in = input
+			reached[id] = true
+		} else {
+			var parents []any
+			for _, e := range doc.Edges {
+				if e.TargetID != id || !reached[e.SourceID] || !edgeActive(e, byID[e.SourceID], handles) {
+					continue
+				}
+				parents = append(parents, outputs[e.SourceID])
+			}
+			if len(parents) == 0 {
+				continue // branch not taken
+			}
+			reached[id] = true
+			in = mergeAll(parents)
+		}
+
+		out, matched, err := evalNode(n, in)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", n.label(), err)
+		}
+		outputs[id] = out
+		if n.Type == NodeTypeSwitch {
+			handles[id] = matched
+		}
+		if n.Type == NodeTypeOutput {
+			result = mergeAll([]any{result, out})
+		}
+	}
+
+	if result == nil {
+		result = map[string]any{}
+	}
+	return result, nil
+}
+
+// EvaluateJSON is Evaluate for raw JSON document and input
+func EvaluateJSON(rawDoc, rawInput []byte) (any, error) {
+	doc, err := Parse(rawDoc)
+	if err != nil {
+		return nil, err
+	}
+	var input any
+	if err := json.Unmarshal(rawInput, &input); err != nil {
+		return nil, fmt.Errorf("invalid input JSON: %w", err)
+	}
+	return Evaluate(doc, input)
+}
+
+func edgeActive(e Edge, src Node, handles map[string][]string) bool {
+	if src.Type != NodeTypeSwitch || e.SourceHandle == "" {
+		return true
+	}
+	for _, h := range handles[src.ID] {
+		if h == e.SourceHandle {
+			return true
+		}
+	}
+	return false
+}
+
+// evalNode returns the node output and, for switch nodes, the matched statements
+func evalNode(n Node, in any) (any, []string, error) {
+	switch n.Type {
+	case NodeTypeInput, NodeTypeOutput:
+		return in, nil, nil
+	case NodeTypeDecisionTable:
+		c, err := n.DecisionTable()
+		if err != nil {
+			return nil, nil, err
+		}
+		out, err := evalDecisionTable(c, in)
+		return out, nil, err
+	case NodeTypeExpression:
+		c, err := n.Expressions()
+		if err != nil {
+			return nil, nil, err
+		}
+		out, err := evalExpressions(c, in)
+		return out, nil, err
+	case NodeTypeSwitch:
+		c, err := n.Switch()
+		if err != nil {
+			return nil, nil, err
+		}
+		matched, err := evalSwitch(c, in)
+		return in, matched, err
+	}
+	return nil, nil, fmt.Errorf("%s is not supported by the local evaluator", n.Type)
+}
+
+func evalDecisionTable(c *DecisionTableContent, in any) (any, error) {
+	var collected []any
+	for i, rule := range c.Rules {
+		ok, err := ruleMatches(c.Inputs, rule, in)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		if !ok {
+			continue
+		}
+
+		out := map[string]any{}
+		for _, col := range c.Outputs {
+			cell := strings.TrimSpace(rule[col.ID])
+			if cell == "" {
+				continue
+			}
+			v, err := evalString(cell, &scope{root: in})
+			if err != nil {
+				return nil, fmt.Errorf("rules[%d] output %q: %w", i, col.Field, err)
+			}
+			setPath(out, col.Field, v)
+		}
+		if c.HitPolicy == HitPolicyFirst {
+			return out, nil
+		}
+		collected = append(collected, out)
+	}
+
+	if c.HitPolicy == HitPolicyFirst {
+		return map[string]any{}, nil
+	}
+	if collected == nil {
+		collected = []any{}
+	}
+	return collected, nil
+}
+
+func ruleMatches(inputs []TableColumn, rule map[string]string, in any) (bool, error) {
+	for _, col := range inputs {
+		cell := rule[col.ID]
+		var ok bool
+		var err error
+		if col.Field == "" {
+			// column without a field: the cell is a full boolean expression
+			ok, err = evalCondition(cell, &scope{root: in})
+		} else {
+			var v any
+			if v, err = evalString(col.Field, &scope{root: in}); err == nil {
+				ok, err = unaryTest(cell, v, in)
+			}
+		}
+		if err != nil {
+			return false, fmt.Errorf("input %q: %w", col.Field, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func evalExpressions(c *ExpressionContent, in any) (any, error) {
+	out := map[string]any{}
+	for _, e := range c.Expressions {
+		// earlier results are visible through `$`
+		v, err := evalString(e.Value, &scope{root: in, dollar: out})
+		if err != nil {
+			return nil, fmt.Errorf("expression %q: %w", e.Key, err)
+		}
+		setPath(out, e.Key, v)
+	}
+	return out, nil
+}
+
+func evalSwitch(c *SwitchContent, in any) ([]string, error) {
+	var matched []string
+	for _, st := range c.Statements {
+		ok, err := evalCondition(st.Condition, &scope{root: in})
+		if err != nil {
+			return nil, fmt.Errorf("statement %q: %w", st.ID, err)
+		}
+		if !ok {
+			continue
+		}
+		matched = append(matched, st.ID)
+		if c.HitPolicy == HitPolicyFirst {
+			break
+		}
+	}
+	return matched, nil
+}
+
+// unaryTest evaluates a decision table input cell against value. Cells are
+// either empty/"-" (always true) or a comma separated list of candidates
+// ("'a', 'b'", "[1..5]", "10"), comparisons missing their left side
+// ("> 10", "< 2, > 5") and boolean expressions using `$` for the value.
+func unaryTest(cell string, value, root any) (bool, error) {
+	cell = strings.TrimSpace(cell)
+	if cell == "" || cell == "-" {
+		return true, nil
+	}
+	list, err := parseUnaryTests(cell)
+	if err != nil {
+		return false, err
+	}
+	s := &scope{root: root, dollar: value}
+	for _, n := range list {
+		v, err := n.eval(s)
+		if err != nil {
+			return false, err
+		}
+		var ok bool
+		switch {
+		case usesDollar(n):
+			ok = truthy(v)
+		default:
+			switch v.(type) {
+			case interval, []any:
+				if ok, err = contains(v, value); err != nil {
+					return false, err
+				}
+			default:
+				ok = equal(value, v)
+			}
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func evalString(src string, s *scope) (any, error) {
+	n, err := parseExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	return n.eval(s)
+}
+
+// evalCondition evaluates a boolean expression; empty conditions are true
+func evalCondition(src string, s *scope) (bool, error) {
+	if strings.TrimSpace(src) == "" {
+		return true, nil
+	}
+	v, err := evalString(src, s)
+	if err != nil {
+		return false, err
+	}
+	return truthy(v), nil
+}
+
+// setPath assigns v at a dotted path ("a.b.c"), creating objects as needed
+func setPath(m map[string]any, path string, v any) {
+	parts := strings.Split(path, ".")
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := m[p].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[p] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = v
+}
+
+// mergeAll deep-merges objects left to right; a non-object value replaces
+func mergeAll(vals []any) any {
+	var out any
+	for _, v := range vals {
+		out = merge(out, v)
+	}
+	return out
+}
+
+func merge(dst, src any) any {
+	sm, ok := src.(map[string]any)
+	if !ok {
+		if src == nil {
+			return dst
+		}
+		return src
+	}
+	dm, ok := dst.(map[string]any)
+	if !ok {
+		dm = map[string]any{}
+	} else {
+		cp := make(map[string]any, len(dm))
+		for k, v := range dm {
+			cp[k] = v
+		}
+		dm = cp
+	}
+	for k, v := range sm {
+		dm[k] = merge(dm[k], v)
+	}
+	return dm
+}
+
+// topoSort orders node IDs so that every node comes after its parents.
+// Validate has already rejected cycles.
+func topoSort(doc *Document) []string {
+	indeg := map[string]int{}
+	adj := map[string][]string{}
+	for _, e := range doc.Edges {
+		adj[e.SourceID] = append(adj[e.SourceID], e.TargetID)
+		indeg[e.TargetID]++
+	}
+	var queue, order []string
+	for _, n := range doc.Nodes {
+		if indeg[n.ID] == 0 {
+			queue = append(queue, n.ID)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		order = append(order, id)
+		for _, next := range adj[id] {
+			indeg[next]--
+			if indeg[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return order
+}
@@ -0,0 +1,138 @@
+package jdm
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tableJSON is a decision table on cart.total with a discount output
+func tableJSON(id, hitPolicy string) string {
+	return `{"id":"` + id + `","type":"decisionTableNode","name":"` + id + `","content":{
+		"hitPolicy":"` + hitPolicy + `",
+		"inputs":[{"id":"i1","field":"cart.total"}],
+		"outputs":[{"id":"o1","field":"discount"}],
+		"rules":[
+			{"_id":"r1","i1":">= 100","o1":"0.1"},
+			{"_id":"r2","i1":">= 50","o1":"0.05"},
+			{"_id":"r3","i1":"","o1":"0"}
+		]}}`
+}
+
+// switchJSON routes to the "big" branch when total > 100 and to the "gold"
+// branch for gold customers
+func switchJSON(hitPolicy string) string {
+	return `{"id":"sw","type":"switchNode","content":{"hitPolicy":"` + hitPolicy + `","statements":[
+		{"id":"s_big","condition":"cart.total > 100"},
+		{"id":"s_gold","condition":"tier == 'gold'"}
+	]}}`
+}
+
+func handleEdge(id, from, to, handle string) string {
+	return `{"id":"` + id + `","sourceId":"` + from + `","targetId":"` + to + `","sourceHandle":"` + handle + `"}`
+}
+
+func TestEvaluate(t *testing.T) {
+	branches := inputJSON + "," + outputJSON + `,
+		{"id":"big","type":"expressionNode","content":{"expressions":[{"id":"x1","key":"shipping.free","value":"true"}]}},
+		{"id":"gold","type":"expressionNode","content":{"expressions":[{"id":"x2","key":"shipping.priority","value":"'high'"},{"id":"x3","key":"points","value":"cart.total * 2"}]}}`
+	branchEdges := edge("e1", "in", "sw") + "," +
+		handleEdge("e2", "sw", "big", "s_big") + "," + handleEdge("e3", "sw", "gold", "s_gold") + "," +
+		edge("e4", "big", "out") + "," + edge("e5", "gold", "out")
+
+	for _, tc := range []struct {
+		name  string
+		doc   string
+		input string
+		want  string
+	}{
+		{
+			"table first",
+			doc(inputJSON+","+tableJSON("t", "first")+","+outputJSON, edge("e1", "in", "t")+","+edge("e2", "t", "out")),
+			`{"cart":{"total":120}}`,
+			`{"discount":0.1}`,
+		},
+		{
+			"table first falls through",
+			doc(inputJSON+","+tableJSON("t", "first")+","+outputJSON, edge("e1", "in", "t")+","+edge("e2", "t", "out")),
+			`{"cart":{"total":10}}`,
+			`{"discount":0}`,
+		},
+		{
+			"table collect",
+			doc(inputJSON+","+tableJSON("t", "collect")+","+outputJSON, edge("e1", "in", "t")+","+edge("e2", "t", "out")),
+			`{"cart":{"total":60}}`,
+			`[{"discount":0.05},{"discount":0}]`,
+		},
+		{
+			"expressions see earlier results through $",
+			doc(inputJSON+`,{"id":"x","type":"expressionNode","content":{"expressions":[
+				{"id":"a","key":"net","value":"cart.total - 20"},
+				{"id":"b","key":"tax","value":"$.net * 0.5"}]}},`+outputJSON, edge("e1", "in", "x")+","+edge("e2", "x", "out")),
+			`{"cart":{"total":100}}`,
+			`{"net":80,"tax":40}`,
+		},
+		{
+			"switch first takes one branch",
+			doc(branches+","+switchJSON("first"), branchEdges),
+			`{"cart":{"total":150},"tier":"gold"}`,
+			`{"shipping":{"free":true}}`,
+		},
+		{
+			"switch collect merges branches",
+			doc(branches+","+switchJSON("collect"), branchEdges),
+			`{"cart":{"total":150},"tier":"gold"}`,
+			`{"shipping":{"free":true,"priority":"high"},"points":300}`,
+		},
+		{
+			"switch with no match",
+			doc(branches+","+switchJSON("collect"), branchEdges),
+			`{"cart":{"total":10},"tier":"silver"}`,
+			`{}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := EvaluateJSON([]byte(tc.doc), []byte(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want any
+			if err := json.Unmarshal([]byte(tc.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"invalid document", doc(outputJSON, ""), "invalid JDM document"},
+		{
+			"function node",
+			doc(inputJSON+`,{"id":"fn","type":"functionNode","content":"export const handler = () => ({})"},`+outputJSON,
+				edge("e1", "in", "fn")+","+edge("e2", "fn", "out")),
+			"functionNode is not supported",
+		},
+		{
+			"non-finite result",
+			doc(inputJSON+`,{"id":"x","type":"expressionNode","content":{"expressions":[{"id":"a","key":"r","value":"(0 - 8) ^ 0.5"}]}},`+outputJSON,
+				edge("e1", "in", "x")+","+edge("e2", "x", "out")),
+			`expression "r": -8 ^ 0.5 is not a finite number`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := EvaluateJSON([]byte(tc.doc), []byte(`{}`))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,625 @@
+package jdm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// -----------------------------------------------------------------------------
+// Expression language (subset of the ZEN expression language used by GoRules)
+//
+// Supported: number/string/bool/null literals, arrays, objects, intervals
+// ([1..10], (0..1]), member and index access, arithmetic (+ - * / % ^),
+// comparisons, `in` / `not in`, `and` / `or` / `not`, `??`, `cond ? a : b`
+// and the builtins listed in builtins.
+// -----------------------------------------------------------------------------
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// comparisonOps may start a unary test item, comparing `$` to the rest
+var comparisonOps = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// longest operators first
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||", "??", "..",
+	"<", ">", "+", "-", "*", "/", "%", "^", "!", "?", ":",
+	"(", ")", "[", "]", "{", "}", ",", ".",
+}
+
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		r, _ := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			// a dot belongs to the number only when followed by a digit (1..10 is a range)
+			if i+1 < len(src) && src[i] == '.' && src[i+1] >= '0' && src[i+1] <= '9' {
+				i++
+				for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+					i++
+				}
+			}
+			text := src[start:i]
+			n, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", text, start)
+			}
+			toks = append(toks, token{kind: tokNumber, text: text, num: n, pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			var sb strings.Builder
+			closed := false
+			for i < len(src) {
+				if src[i] == '\\' && i+1 < len(src) {
+					esc, n := utf8.DecodeRuneInString(src[i+1:])
+					sb.WriteRune(esc)
+					i += 1 + n
+					continue
+				}
+				if src[i] == c {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteByte(src[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			toks = append(toks, token{kind: tokString, text: sb.String(), pos: start})
+		case isIdentRune(r, false):
+			start := i
+			for i < len(src) {
+				r, n := utf8.DecodeRuneInString(src[i:])
+				if !isIdentRune(r, true) {
+					break
+				}
+				i += n
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					toks = append(toks, token{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at %d", r, i)
+			}
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// isIdentRune reports whether r can start (or, with rest, continue) an
+// identifier; letters include non-ASCII ones such as "é" or "ß"
+func isIdentRune(r rune, rest bool) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r) || (rest && r >= '0' && r <= '9')
+}
+
+// -----------------------------------------------------------------------------
+// AST
+// -----------------------------------------------------------------------------
+
+type exprNode interface {
+	eval(s *scope) (any, error)
+}
+
+type (
+	literalExpr struct{ v any }
+	identExpr   struct{ name string }
+	memberExpr  struct {
+		obj  exprNode
+		name string
+	}
+	indexExpr struct{ obj, idx exprNode }
+	unaryExpr struct {
+		op string
+		x  exprNode
+	}
+	binaryExpr struct {
+		op   string
+		l, r exprNode
+	}
+	ternaryExpr struct{ cond, a, b exprNode }
+	arrayExpr   struct{ elems []exprNode }
+	objectExpr  struct {
+		keys []string
+		vals []exprNode
+	}
+	intervalExpr struct {
+		lo, hi             exprNode
+		loClosed, hiClosed bool
+	}
+	callExpr struct {
+		name string
+		args []exprNode
+	}
+)
+
+// -----------------------------------------------------------------------------
+// Parser
+// -----------------------------------------------------------------------------
+
+type parser struct {
+	toks []token
+	pos  int
+	src  string
+}
+
+// parseExpression parses a single standalone expression
+func parseExpression(src string) (exprNode, error) {
+	list, err := parseList(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) != 1 {
+		return nil, fmt.Errorf("expression %q: expected a single expression", src)
+	}
+	return list[0], nil
+}
+
+// parseList parses comma separated expressions
+func parseList(src string) ([]exprNode, error) { return parseItems(src, false) }
+
+// parseUnaryTests parses a decision table input cell: a comma separated list
+// where an item starting with a comparison operator ("> 5, < 2") compares `$`
+func parseUnaryTests(src string) ([]exprNode, error) { return parseItems(src, true) }
+
+func parseItems(src string, unary bool) ([]exprNode, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", src, err)
+	}
+	p := &parser{toks: toks, src: src}
+	var out []exprNode
+	for {
+		var n exprNode
+		if unary && p.peek().kind == tokOp && comparisonOps[p.peek().text] {
+			op := p.next().text
+			var r exprNode
+			if r, err = p.parseAdditive(); err == nil {
+				n = &binaryExpr{op: op, l: &identExpr{name: "$"}, r: r}
+			}
+		} else {
+			n, err = p.parseTernary()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("expression %q: %w", src, err)
+		}
+		out = append(out, n)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("expression %q: unexpected %q at %d", src, p.peek().text, p.peek().pos)
+	}
+	return out, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n]
+	}
+	return p.toks[len(p.toks)-1]
+}
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isOp reports whether the current token is the operator or keyword text
+func (p *parser) isOp(text string) bool {
+	t := p.peek()
+	return (t.kind == tokOp || t.kind == tokIdent) && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.isOp(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return fmt.Errorf("expected %q at end of input", text)
+		}
+		return fmt.Errorf("expected %q at %d, found %q", text, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *parser) parseTernary() (exprNode, error) {
+	cond, err := p.parseCoalesce()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return cond, nil
+	}
+	a, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	b, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return &ternaryExpr{cond: cond, a: a, b: b}, nil
+}
+
+func (p *parser) parseCoalesce() (exprNode, error) {
+	l, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("??") {
+		r, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: "??", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseOr() (exprNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") || p.accept("||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: "or", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (exprNode, error) {
+	l, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") || p.accept("&&") {
+		r, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: "and", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseComparison() (exprNode, error) {
+	l, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op string
+		switch {
+		case p.isOp("==") || p.isOp("!=") || p.isOp("<") || p.isOp("<=") || p.isOp(">") || p.isOp(">=") || p.isOp("in"):
+			op = p.next().text
+		case p.isOp("not") && p.peekAt(1).kind == tokIdent && p.peekAt(1).text == "in":
+			p.pos += 2
+			op = "not in"
+		default:
+			return l, nil
+		}
+		r, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+}
+
+func (p *parser) parseAdditive() (exprNode, error) {
+	l, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		op := p.next().text
+		r, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseMultiplicative() (exprNode, error) {
+	l, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+		op := p.next().text
+		r, err := p.parsePower()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parsePower() (exprNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("^") {
+		r, err := p.parsePower() // right associative
+		if err != nil {
+			return nil, err
+		}
+		return &binaryExpr{op: "^", l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (exprNode, error) {
+	switch {
+	case p.isOp("-") || p.isOp("+"):
+		op := p.next().text
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: op, x: x}, nil
+	case p.isOp("!") || p.isOp("not"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "not", x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (exprNode, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, fmt.Errorf("expected field name after '.' at %d", t.pos)
+			}
+			x = &memberExpr{obj: x, name: t.text}
+		case p.isOp("["):
+			p.next()
+			idx, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexExpr{obj: x, idx: idx}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literalExpr{v: t.num}, nil
+	case tokString:
+		return &literalExpr{v: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalExpr{v: true}, nil
+		case "false":
+			return &literalExpr{v: false}, nil
+		case "null":
+			return &literalExpr{v: nil}, nil
+		}
+		if p.accept("(") {
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := builtins[t.text]; !ok {
+				return nil, fmt.Errorf("unknown function %q", t.text)
+			}
+			return &callExpr{name: t.text, args: args}, nil
+		}
+		return &identExpr{name: t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if p.accept("..") {
+				return p.parseIntervalEnd(x, false)
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			if p.accept("]") {
+				return &arrayExpr{}, nil
+			}
+			first, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if p.accept("..") {
+				return p.parseIntervalEnd(first, true)
+			}
+			elems := []exprNode{first}
+			for p.accept(",") {
+				e, err := p.parseTernary()
+				if err != nil {
+					return nil, err
+				}
+				elems = append(elems, e)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			return &arrayExpr{elems: elems}, nil
+		case "{":
+			obj := &objectExpr{}
+			for !p.accept("}") {
+				if len(obj.keys) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				k := p.next()
+				if k.kind != tokIdent && k.kind != tokString {
+					return nil, fmt.Errorf("expected object key at %d", k.pos)
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				v, err := p.parseTernary()
+				if err != nil {
+					return nil, err
+				}
+				obj.keys = append(obj.keys, k.text)
+				obj.vals = append(obj.vals, v)
+			}
+			return obj, nil
+		}
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
+
+func (p *parser) parseIntervalEnd(lo exprNode, loClosed bool) (exprNode, error) {
+	hi, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.accept("]"):
+		return &intervalExpr{lo: lo, hi: hi, loClosed: loClosed, hiClosed: true}, nil
+	case p.accept(")"):
+		return &intervalExpr{lo: lo, hi: hi, loClosed: loClosed, hiClosed: false}, nil
+	}
+	return nil, fmt.Errorf("expected ']' or ')' to close interval at %d", p.peek().pos)
+}
+
+func (p *parser) parseArgs(closing string) ([]exprNode, error) {
+	var args []exprNode
+	if p.accept(closing) {
+		return args, nil
+	}
+	for {
+		a, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+		if p.accept(closing) {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// usesDollar reports whether the expression references the `$` symbol
+func usesDollar(n exprNode) bool {
+	switch x := n.(type) {
+	case *identExpr:
+		return x.name == "$"
+	case *memberExpr:
+		return usesDollar(x.obj)
+	case *indexExpr:
+		return usesDollar(x.obj) || usesDollar(x.idx)
+	case *unaryExpr:
+		return usesDollar(x.x)
+	case *binaryExpr:
+		return usesDollar(x.l) || usesDollar(x.r)
+	case *ternaryExpr:
+		return usesDollar(x.cond) || usesDollar(x.a) || usesDollar(x.b)
+	case *arrayExpr:
+		for _, e := range x.elems {
+			if usesDollar(e) {
+				return true
+			}
+		}
+	case *objectExpr:
+		for _, e := range x.vals {
+			if usesDollar(e) {
+				return true
+			}
+		}
+	case *intervalExpr:
+		return usesDollar(x.lo) || usesDollar(x.hi)
+	case *callExpr:
+		for _, e := range x.args {
+			if usesDollar(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
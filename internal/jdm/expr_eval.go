@@ -0,0 +1,630 @@
+package jdm
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// scope holds the values visible to an expression: fields of root are plain
+// identifiers and `$` is the current value (table column, previous results)
+type scope struct {
+	root   any
+	dollar any
+}
+
+// interval is the runtime value of [lo..hi] / (lo..hi)
+type interval struct {
+	lo, hi             float64
+	loClosed, hiClosed bool
+}
+
+func (iv interval) contains(n float64) bool {
+	if n < iv.lo || (n == iv.lo && !iv.loClosed) {
+		return false
+	}
+	if n > iv.hi || (n == iv.hi && !iv.hiClosed) {
+		return false
+	}
+	return true
+}
+
+func (e *literalExpr) eval(*scope) (any, error) { return e.v, nil }
+
+func (e *identExpr) eval(s *scope) (any, error) {
+	if e.name == "$" {
+		return s.dollar, nil
+	}
+	return field(s.root, e.name), nil
+}
+
+func (e *memberExpr) eval(s *scope) (any, error) {
+	obj, err := e.obj.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	return field(obj, e.name), nil
+}
+
+func (e *indexExpr) eval(s *scope) (any, error) {
+	obj, err := e.obj.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := e.idx.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch o := obj.(type) {
+	case []any:
+		n, ok := idx.(float64)
+		if !ok {
+			return nil, fmt.Errorf("array index must be a number, got %s", typeName(idx))
+		}
+		i := int(n)
+		if i < 0 {
+			i += len(o)
+		}
+		if i < 0 || i >= len(o) {
+			return nil, nil
+		}
+		return o[i], nil
+	case map[string]any:
+		k, ok := idx.(string)
+		if !ok {
+			return nil, fmt.Errorf("object key must be a string, got %s", typeName(idx))
+		}
+		return o[k], nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("cannot index %s", typeName(obj))
+}
+
+func (e *unaryExpr) eval(s *scope) (any, error) {
+	x, err := e.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "not":
+		return !truthy(x), nil
+	case "-", "+":
+		n, ok := x.(float64)
+		if !ok {
+			return nil, fmt.Errorf("unary %s expects a number, got %s", e.op, typeName(x))
+		}
+		if e.op == "-" {
+			return -n, nil
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", e.op)
+}
+
+func (e *binaryExpr) eval(s *scope) (any, error) {
+	l, err := e.l.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// short-circuit operators
+	switch e.op {
+	case "and":
+		if !truthy(l) {
+			return false, nil
+		}
+		r, err := e.r.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		return truthy(r), nil
+	case "or":
+		if truthy(l) {
+			return true, nil
+		}
+		r, err := e.r.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		return truthy(r), nil
+	case "??":
+		if l != nil {
+			return l, nil
+		}
+		return e.r.eval(s)
+	}
+
+	r, err := e.r.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "in":
+		return contains(r, l)
+	case "not in":
+		ok, err := contains(r, l)
+		if err != nil {
+			return nil, err
+		}
+		return !ok, nil
+	case "<", "<=", ">", ">=":
+		if l == nil || r == nil {
+			return false, nil // missing fields never compare
+		}
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch e.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "+":
+		if ls, ok := l.(string); ok {
+			if rs, ok := r.(string); ok {
+				return ls + rs, nil
+			}
+		}
+	}
+
+	ln, lok := l.(float64)
+	rn, rok := r.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator %s is not defined for %s and %s", e.op, typeName(l), typeName(r))
+	}
+	var n float64
+	switch e.op {
+	case "+":
+		n = ln + rn
+	case "-":
+		n = ln - rn
+	case "*":
+		n = ln * rn
+	case "/":
+		if rn == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		n = ln / rn
+	case "%":
+		if rn == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		n = math.Mod(ln, rn)
+	case "^":
+		n = math.Pow(ln, rn)
+	default:
+		return nil, fmt.Errorf("unknown operator %s", e.op)
+	}
+	// JSON (and Terraform) numbers cannot hold NaN or ±Inf, e.g. (0-8) ^ 0.5
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return nil, fmt.Errorf("%v %s %v is not a finite number", ln, e.op, rn)
+	}
+	return n, nil
+}
+
+func (e *ternaryExpr) eval(s *scope) (any, error) {
+	c, err := e.cond.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(c) {
+		return e.a.eval(s)
+	}
+	return e.b.eval(s)
+}
+
+func (e *arrayExpr) eval(s *scope) (any, error) {
+	out := make([]any, 0, len(e.elems))
+	for _, el := range e.elems {
+		v, err := el.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (e *objectExpr) eval(s *scope) (any, error) {
+	out := make(map[string]any, len(e.keys))
+	for i, k := range e.keys {
+		v, err := e.vals[i].eval(s)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (e *intervalExpr) eval(s *scope) (any, error) {
+	lo, err := e.lo.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := e.hi.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	ln, lok := lo.(float64)
+	hn, hok := hi.(float64)
+	if !lok || !hok {
+		return nil, fmt.Errorf("interval bounds must be numbers, got %s and %s", typeName(lo), typeName(hi))
+	}
+	return interval{lo: ln, hi: hn, loClosed: e.loClosed, hiClosed: e.hiClosed}, nil
+}
+
+func (e *callExpr) eval(s *scope) (any, error) {
+	args := make([]any, 0, len(e.args))
+	for _, a := range e.args {
+		v, err := a.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	v, err := builtins[e.name](args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", e.name, err)
+	}
+	if n, ok := v.(float64); ok && (math.IsNaN(n) || math.IsInf(n, 0)) {
+		return nil, fmt.Errorf("%s(): result is not a finite number", e.name)
+	}
+	return v, nil
+}
+
+// -----------------------------------------------------------------------------
+// Value helpers
+// -----------------------------------------------------------------------------
+
+// field reads a key from an object; anything else yields null
+func field(obj any, name string) any {
+	if m, ok := obj.(map[string]any); ok {
+		return m[name]
+	}
+	return nil
+}
+
+func truthy(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case float64:
+		return x != 0
+	case string:
+		return x != ""
+	}
+	return true
+}
+
+func equal(a, b any) bool {
+	if an, ok := a.(float64); ok {
+		bn, ok := b.(float64)
+		return ok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compare(a, b any) (int, error) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1, nil
+			case x > y:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", typeName(a), typeName(b))
+}
+
+// contains implements `needle in haystack`
+func contains(haystack, needle any) (bool, error) {
+	switch h := haystack.(type) {
+	case []any:
+		for _, v := range h {
+			if equal(v, needle) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case interval:
+		n, ok := needle.(float64)
+		return ok && h.contains(n), nil
+	case string:
+		s, ok := needle.(string)
+		return ok && strings.Contains(h, s), nil
+	case map[string]any:
+		k, ok := needle.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found := h[k]
+		return found, nil
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("operator in is not defined for %s", typeName(haystack))
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case interval:
+		return "interval"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// -----------------------------------------------------------------------------
+// Builtin functions
+// -----------------------------------------------------------------------------
+
+type builtinFunc func(args []any) (any, error)
+
+var builtins = map[string]builtinFunc{
+	"len":        fnLen,
+	"upper":      stringFn(strings.ToUpper),
+	"lower":      stringFn(strings.ToLower),
+	"trim":       stringFn(strings.TrimSpace),
+	"startsWith": stringPredicate(strings.HasPrefix),
+	"endsWith":   stringPredicate(strings.HasSuffix),
+	"contains":   fnContains,
+	"abs":        numberFn(math.Abs),
+	"floor":      numberFn(math.Floor),
+	"ceil":       numberFn(math.Ceil),
+	"round":      fnRound,
+	"min":        aggregate(func(xs []float64) float64 { sort.Float64s(xs); return xs[0] }),
+	"max":        aggregate(func(xs []float64) float64 { sort.Float64s(xs); return xs[len(xs)-1] }),
+	"sum":        aggregate(func(xs []float64) float64 { return sumOf(xs) }),
+	"avg":        aggregate(func(xs []float64) float64 { return sumOf(xs) / float64(len(xs)) }),
+	"number":     fnNumber,
+	"string":     fnString,
+	"isNumeric":  fnIsNumeric,
+	"keys":       fnKeys,
+	"values":     fnValues,
+}
+
+func arity(args []any, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %d argument(s), got %d", n, len(args))
+	}
+	return nil
+}
+
+func fnLen(args []any) (any, error) {
+	if err := arity(args, 1); err != nil {
+		return nil, err
+	}
+	switch x := args[0].(type) {
+	case string:
+		return float64(utf8.RuneCountInString(x)), nil
+	case []any:
+		return float64(len(x)), nil
+	case map[string]any:
+		return float64(len(x)), nil
+	}
+	return nil, fmt.Errorf("not defined for %s", typeName(args[0]))
+}
+
+func stringFn(f func(string) string) builtinFunc {
+	return func(args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("expects a string, got %s", typeName(args[0]))
+		}
+		return f(s), nil
+	}
+}
+
+func stringPredicate(f func(string, string) bool) builtinFunc {
+	return func(args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+		s, ok1 := args[0].(string)
+		p, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("expects strings, got %s and %s", typeName(args[0]), typeName(args[1]))
+		}
+		return f(s, p), nil
+	}
+}
+
+func fnContains(args []any) (any, error) {
+	if err := arity(args, 2); err != nil {
+		return nil, err
+	}
+	return contains(args[0], args[1])
+}
+
+func numberFn(f func(float64) float64) builtinFunc {
+	return func(args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+		n, ok := args[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("expects a number, got %s", typeName(args[0]))
+		}
+		return f(n), nil
+	}
+}
+
+func fnRound(args []any) (any, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, fmt.Errorf("expected 1 or 2 arguments, got %d", len(args))
+	}
+	n, ok := args[0].(float64)
+	if !ok {
+		return nil, fmt.Errorf("expects a number, got %s", typeName(args[0]))
+	}
+	digits := 0.0
+	if len(args) == 2 {
+		if digits, ok = args[1].(float64); !ok {
+			return nil, fmt.Errorf("digits must be a number, got %s", typeName(args[1]))
+		}
+	}
+	p := math.Pow(10, digits)
+	return math.Round(n*p) / p, nil
+}
+
+// aggregate accepts either one array argument or several numbers
+func aggregate(f func([]float64) float64) builtinFunc {
+	return func(args []any) (any, error) {
+		items := args
+		if len(args) == 1 {
+			if arr, ok := args[0].([]any); ok {
+				items = arr
+			}
+		}
+		if len(items) == 0 {
+			return nil, nil
+		}
+		xs := make([]float64, 0, len(items))
+		for _, it := range items {
+			n, ok := it.(float64)
+			if !ok {
+				return nil, fmt.Errorf("expects numbers, got %s", typeName(it))
+			}
+			xs = append(xs, n)
+		}
+		return f(xs), nil
+	}
+}
+
+func sumOf(xs []float64) float64 {
+	total := 0.0
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+func fnNumber(args []any) (any, error) {
+	if err := arity(args, 1); err != nil {
+		return nil, err
+	}
+	switch x := args[0].(type) {
+	case float64:
+		return x, nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", x)
+		}
+		return n, nil
+	case bool:
+		if x {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	}
+	return nil, fmt.Errorf("not defined for %s", typeName(args[0]))
+}
+
+func fnString(args []any) (any, error) {
+	if err := arity(args, 1); err != nil {
+		return nil, err
+	}
+	switch x := args[0].(type) {
+	case string:
+		return x, nil
+	case float64:
+		return formatNumber(x), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case nil:
+		return "null", nil
+	}
+	return nil, fmt.Errorf("not defined for %s", typeName(args[0]))
+}
+
+func fnIsNumeric(args []any) (any, error) {
+	if err := arity(args, 1); err != nil {
+		return nil, err
+	}
+	switch x := args[0].(type) {
+	case float64:
+		return true, nil
+	case string:
+		_, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return err == nil, nil
+	}
+	return false, nil
+}
+
+func fnKeys(args []any) (any, error) {
+	if err := arity(args, 1); err != nil {
+		return nil, err
+	}
+	m, ok := args[0].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expects an object, got %s", typeName(args[0]))
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]any, len(keys))
+	for i, k := range keys {
+		out[i] = k
+	}
+	return out, nil
+}
+
+func fnValues(args []any) (any, error) {
+	keys, err := fnKeys(args)
+	if err != nil {
+		return nil, err
+	}
+	m := args[0].(map[string]any)
+	out := make([]any, 0, len(m))
+	for _, k := range keys.([]any) {
+		out = append(out, m[k.(string)])
+	}
+	return out, nil
+}
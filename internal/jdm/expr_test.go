@@ -0,0 +1,142 @@
+package jdm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvalExpression(t *testing.T) {
+	root := map[string]any{
+		"cart":   map[string]any{"total": 150.0, "items": []any{"a", "b"}},
+		"tier":   "gold",
+		"precio": 10.0,
+		"größe":  2.0,
+	}
+	for _, tc := range []struct {
+		src  string
+		want any
+	}{
+		// literals
+		{`42`, 42.0},
+		{`1.5`, 1.5},
+		{`"double"`, "double"},
+		{`'single'`, "single"},
+		{`'it\'s'`, "it's"},
+		{`"café"`, "café"},
+		{`true`, true},
+		{`null`, nil},
+		{`[1, "a"]`, []any{1.0, "a"}},
+		{`{a: 1, "b c": 2}`, map[string]any{"a": 1.0, "b c": 2.0}},
+
+		// precedence and associativity
+		{`1 + 2 * 3`, 7.0},
+		{`(1 + 2) * 3`, 9.0},
+		{`10 - 4 - 3`, 3.0},
+		{`2 ^ 3 ^ 2`, 512.0},
+		{`-2 ^ 2`, 4.0},
+		{`7 % 4 * 2`, 6.0},
+		{`1 + 1 == 2 and 3 > 2`, true},
+		{`false or true and false`, false},
+		{`not false and false`, false},
+		{`null ?? 1 + 1`, 2.0},
+		{`1 > 2 ? "a" : 2 > 1 ? "b" : "c"`, "b"},
+
+		// fields, ranges and membership
+		{`cart.total * 2`, 300.0},
+		{`cart.items[1]`, "b"},
+		{`cart.items[-1]`, "b"},
+		{`missing.field`, nil},
+		{`cart.total in [100..150]`, true},
+		{`cart.total in [100..150)`, false},
+		{`5 in (0..10)`, true},
+		{`tier in ["gold", "silver"]`, true},
+		{`tier not in ["gold"]`, false},
+		{`"a" + "b"`, "ab"},
+
+		// identifiers are decoded as UTF-8
+		{`precio + größe`, 12.0},
+
+		// builtins
+		{`len("café")`, 4.0},
+		{`round(2.345, 2)`, 2.35},
+		{`max(cart.total, 3)`, 150.0},
+	} {
+		t.Run(tc.src, func(t *testing.T) {
+			got, err := evalString(tc.src, &scope{root: root})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src  string
+		want string
+	}{
+		{`1 +`, "unexpected end of input"},
+		{`"open`, "unterminated string"},
+		{`1 # 2`, `unexpected character '#'`},
+		{`1 € 2`, `unexpected character '€'`},
+		{`nope(1)`, `unknown function "nope"`},
+		{`1 / 0`, "division by zero"},
+		{`"a" * 2`, "operator * is not defined for string and number"},
+		{`(0 - 8) ^ 0.5`, "is not a finite number"},
+		{`10 ^ 400`, "is not a finite number"},
+		{`round(1, 400)`, "round(): result is not a finite number"},
+	} {
+		t.Run(tc.src, func(t *testing.T) {
+			_, err := evalString(tc.src, &scope{})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnaryTest(t *testing.T) {
+	for _, tc := range []struct {
+		cell  string
+		value any
+		want  bool
+	}{
+		{``, 1.0, true},
+		{`-`, nil, true},
+		{`10`, 10.0, true},
+		{`10`, 11.0, false},
+		{`'a', 'b'`, "b", true},
+		{`'a', 'b'`, "c", false},
+		{`[1..5]`, 5.0, true},
+		{`(1..5)`, 5.0, false},
+		{`[1..5], [10..20]`, 15.0, true},
+		{`> 10`, 11.0, true},
+		{`>= 10`, 10.0, true},
+		{`!= 'x'`, "y", true},
+		{`> 10`, nil, false},
+
+		// the implicit `$` applies to every item
+		{`> 5, < 2`, 7.0, true},
+		{`> 5, < 2`, 1.0, true},
+		{`> 5, < 2`, 3.0, false},
+		{`< 2, [4..6], 'n/a'`, 5.0, true},
+
+		// explicit `$`
+		{`$ > 1 and $ < 3`, 2.0, true},
+		{`len($) == 3`, "abc", true},
+	} {
+		t.Run(tc.cell, func(t *testing.T) {
+			got, err := unaryTest(tc.cell, tc.value, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("unaryTest(%q, %v) = %v, want %v", tc.cell, tc.value, got, tc.want)
+			}
+		})
+	}
+}
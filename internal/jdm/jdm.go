@@ -0,0 +1,150 @@
+// Package jdm parses GoRules JSON Decision Model (JDM) documents.
+package jdm
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Node types supported in a JDM graph
+const (
+	NodeTypeInput         = "inputNode"
+	NodeTypeOutput        = "outputNode"
+	NodeTypeDecisionTable = "decisionTableNode"
+	NodeTypeExpression    = "expressionNode"
+	NodeTypeFunction      = "functionNode"
+	NodeTypeSwitch        = "switchNode"
+)
+
+// Hit policies for decision tables and switch nodes
+const (
+	HitPolicyFirst   = "first"
+	HitPolicyCollect = "collect"
+)
+
+var knownNodeTypes = map[string]bool{
+	NodeTypeInput:         true,
+	NodeTypeOutput:        true,
+	NodeTypeDecisionTable: true,
+	NodeTypeExpression:    true,
+	NodeTypeFunction:      true,
+	NodeTypeSwitch:        true,
+}
+
+// Document is a decision graph: nodes connected by directed edges
+type Document struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+type Node struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Name    string          `json:"name"`
+	Content json.RawMessage `json:"content,omitempty"` // shape depends on Type
+}
+
+type Edge struct {
+	ID           string `json:"id"`
+	SourceID     string `json:"sourceId"`
+	TargetID     string `json:"targetId"`
+	SourceHandle string `json:"sourceHandle,omitempty"` // switch statement ID
+}
+
+// -----------------------------------------------------------------------------
+// Node contents
+// -----------------------------------------------------------------------------
+
+type DecisionTableContent struct {
+	HitPolicy string              `json:"hitPolicy"`
+	Inputs    []TableColumn       `json:"inputs"`
+	Outputs   []TableColumn       `json:"outputs"`
+	Rules     []map[string]string `json:"rules"` // column ID -> cell; "_id" is the rule ID
+}
+
+type TableColumn struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Field string `json:"field"`
+}
+
+type ExpressionContent struct {
+	Expressions []Expression `json:"expressions"`
+}
+
+type Expression struct {
+	ID    string `json:"id"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type SwitchContent struct {
+	HitPolicy  string      `json:"hitPolicy"`
+	Statements []Statement `json:"statements"`
+}
+
+type Statement struct {
+	ID        string `json:"id"`
+	Condition string `json:"condition"` // empty condition always matches
+}
+
+// Parse decodes a JDM document. It only checks that the JSON is well formed;
+// use Validate for structural checks.
+func Parse(raw []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JDM JSON: %w", err)
+	}
+	return &doc, nil
+}
+
+// DecisionTable decodes the content of a decisionTableNode
+func (n Node) DecisionTable() (*DecisionTableContent, error) {
+	var c DecisionTableContent
+	if err := n.decodeContent(&c); err != nil {
+		return nil, err
+	}
+	if c.HitPolicy == "" {
+		c.HitPolicy = HitPolicyFirst
+	}
+	return &c, nil
+}
+
+// Expressions decodes the content of an expressionNode
+func (n Node) Expressions() (*ExpressionContent, error) {
+	var c ExpressionContent
+	if err := n.decodeContent(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Switch decodes the content of a switchNode
+func (n Node) Switch() (*SwitchContent, error) {
+	var c SwitchContent
+	if err := n.decodeContent(&c); err != nil {
+		return nil, err
+	}
+	if c.HitPolicy == "" {
+		c.HitPolicy = HitPolicyFirst
+	}
+	return &c, nil
+}
+
+func (n Node) decodeContent(v any) error {
+	if len(n.Content) == 0 || string(n.Content) == "null" {
+		return fmt.Errorf("node %q has no content", n.ID)
+	}
+	if err := json.Unmarshal(n.Content, v); err != nil {
+		return fmt.Errorf("node %q has invalid content: %w", n.ID, err)
+	}
+	return nil
+}
+
+// label identifies a node in messages: `"id" (name)`
+func (n Node) label() string {
+	if n.Name == "" {
+		return fmt.Sprintf("%q", n.ID)
+	}
+	return fmt.Sprintf("%q (%s)", n.ID, n.Name)
+}
@@ -0,0 +1,253 @@
+package jdm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Validate checks the structure of a decision graph and returns one message
+// per problem found. An empty result means the graph is well formed.
+//
+// Checks: node IDs and types, duplicate IDs, edge references, switch handles,
+// node contents, cycles and nodes not reachable from the input node.
+func Validate(doc *Document) []string {
+	errs := []string{}
+	if doc == nil || len(doc.Nodes) == 0 {
+		return append(errs, "document has no nodes")
+	}
+
+	// Nodes: IDs, types and contents
+	byID := map[string]Node{}
+	inputs, outputs := 0, 0
+	for i, n := range doc.Nodes {
+		if n.ID == "" {
+			errs = append(errs, fmt.Sprintf("nodes[%d]: missing id", i))
+			continue
+		}
+		if _, dup := byID[n.ID]; dup {
+			errs = append(errs, fmt.Sprintf("nodes[%d]: duplicate node id %q", i, n.ID))
+			continue
+		}
+		byID[n.ID] = n
+
+		if !knownNodeTypes[n.Type] {
+			errs = append(errs, fmt.Sprintf("node %s: unknown type %q", n.label(), n.Type))
+			continue
+		}
+		switch n.Type {
+		case NodeTypeInput:
+			inputs++
+		case NodeTypeOutput:
+			outputs++
+		}
+		errs = append(errs, validateContent(n)...)
+	}
+	if inputs != 1 {
+		errs = append(errs, fmt.Sprintf("document must have exactly one %s, found %d", NodeTypeInput, inputs))
+	}
+	if outputs == 0 {
+		errs = append(errs, fmt.Sprintf("document must have at least one %s", NodeTypeOutput))
+	}
+
+	// Edges: IDs and references
+	edgeIDs := map[string]bool{}
+	adj := map[string][]string{}
+	for i, e := range doc.Edges {
+		if e.ID == "" {
+			errs = append(errs, fmt.Sprintf("edges[%d]: missing id", i))
+		} else if edgeIDs[e.ID] {
+			errs = append(errs, fmt.Sprintf("edges[%d]: duplicate edge id %q", i, e.ID))
+		} else {
+			edgeIDs[e.ID] = true
+		}
+
+		src, srcOK := byID[e.SourceID]
+		dst, dstOK := byID[e.TargetID]
+		if !srcOK {
+			errs = append(errs, fmt.Sprintf("edge %q: source %q does not exist", e.ID, e.SourceID))
+		}
+		if !dstOK {
+			errs = append(errs, fmt.Sprintf("edge %q: target %q does not exist", e.ID, e.TargetID))
+		}
+		if !srcOK || !dstOK {
+			continue
+		}
+		if e.SourceID == e.TargetID {
+			errs = append(errs, fmt.Sprintf("edge %q: connects node %s to itself", e.ID, src.label()))
+			continue
+		}
+		if src.Type == NodeTypeOutput {
+			errs = append(errs, fmt.Sprintf("edge %q: %s %s cannot have outgoing edges", e.ID, NodeTypeOutput, src.label()))
+		}
+		if dst.Type == NodeTypeInput {
+			errs = append(errs, fmt.Sprintf("edge %q: %s %s cannot have incoming edges", e.ID, NodeTypeInput, dst.label()))
+		}
+		if src.Type == NodeTypeSwitch && e.SourceHandle != "" && !hasStatement(src, e.SourceHandle) {
+			errs = append(errs, fmt.Sprintf("edge %q: switch %s has no statement %q", e.ID, src.label(), e.SourceHandle))
+		}
+		adj[e.SourceID] = append(adj[e.SourceID], e.TargetID)
+	}
+
+	errs = append(errs, findCycles(doc.Nodes, adj)...)
+	errs = append(errs, findDisconnected(doc.Nodes, adj)...)
+	return errs
+}
+
+func validateContent(n Node) []string {
+	var errs []string
+	switch n.Type {
+	case NodeTypeDecisionTable:
+		c, err := n.DecisionTable()
+		if err != nil {
+			return []string{err.Error()}
+		}
+		if c.HitPolicy != HitPolicyFirst && c.HitPolicy != HitPolicyCollect {
+			errs = append(errs, fmt.Sprintf("node %s: unsupported hitPolicy %q", n.label(), c.HitPolicy))
+		}
+		cols := map[string]bool{}
+		for _, col := range append(append([]TableColumn{}, c.Inputs...), c.Outputs...) {
+			if col.ID == "" {
+				errs = append(errs, fmt.Sprintf("node %s: column without id", n.label()))
+				continue
+			}
+			if cols[col.ID] {
+				errs = append(errs, fmt.Sprintf("node %s: duplicate column id %q", n.label(), col.ID))
+			}
+			cols[col.ID] = true
+		}
+		for _, col := range c.Outputs {
+			if col.Field == "" {
+				errs = append(errs, fmt.Sprintf("node %s: output column %q has no field", n.label(), col.ID))
+			}
+		}
+		for i, rule := range c.Rules {
+			for k := range rule {
+				if k != "_id" && k != "_description" && !cols[k] {
+					errs = append(errs, fmt.Sprintf("node %s: rules[%d] references unknown column %q", n.label(), i, k))
+				}
+			}
+		}
+	case NodeTypeExpression:
+		c, err := n.Expressions()
+		if err != nil {
+			return []string{err.Error()}
+		}
+		for i, e := range c.Expressions {
+			if e.Key == "" {
+				errs = append(errs, fmt.Sprintf("node %s: expressions[%d] has no key", n.label(), i))
+			}
+		}
+	case NodeTypeSwitch:
+		c, err := n.Switch()
+		if err != nil {
+			return []string{err.Error()}
+		}
+		if c.HitPolicy != HitPolicyFirst && c.HitPolicy != HitPolicyCollect {
+			errs = append(errs, fmt.Sprintf("node %s: unsupported hitPolicy %q", n.label(), c.HitPolicy))
+		}
+		seen := map[string]bool{}
+		for i, s := range c.Statements {
+			if s.ID == "" {
+				errs = append(errs, fmt.Sprintf("node %s: statements[%d] has no id", n.label(), i))
+			} else if seen[s.ID] {
+				errs = append(errs, fmt.Sprintf("node %s: duplicate statement id %q", n.label(), s.ID))
+			}
+			seen[s.ID] = true
+		}
+	}
+	// stable output: rule keys come from map iteration
+	sort.Strings(errs)
+	return errs
+}
+
+func hasStatement(n Node, id string) bool {
+	c, err := n.Switch()
+	if err != nil {
+		return false
+	}
+	for _, s := range c.Statements {
+		if s.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// findCycles reports every node that is part of a cycle (graphs must be acyclic)
+func findCycles(nodes []Node, adj map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	inCycle := map[string]bool{}
+
+	var visit func(id string, stack []string)
+	visit = func(id string, stack []string) {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, next := range adj[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next, stack)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					inCycle[stack[i]] = true
+					if stack[i] == next {
+						break
+					}
+				}
+			}
+		}
+		state[id] = done
+	}
+
+	var errs []string
+	for _, n := range nodes {
+		if state[n.ID] == unvisited {
+			visit(n.ID, nil)
+		}
+	}
+	for _, n := range nodes {
+		if inCycle[n.ID] {
+			errs = append(errs, fmt.Sprintf("node %s: is part of a cycle", n.label()))
+			delete(inCycle, n.ID) // report duplicates once
+		}
+	}
+	return errs
+}
+
+// findDisconnected reports nodes that cannot be reached from the input node
+func findDisconnected(nodes []Node, adj map[string][]string) []string {
+	reached := map[string]bool{}
+	var queue []string
+	for _, n := range nodes {
+		if n.Type == NodeTypeInput {
+			reached[n.ID] = true
+			queue = append(queue, n.ID)
+		}
+	}
+	if len(queue) == 0 {
+		return nil // already reported as a missing input node
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[id] {
+			if !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	var errs []string
+	for _, n := range nodes {
+		if n.ID != "" && !reached[n.ID] {
+			errs = append(errs, fmt.Sprintf("node %s: is not connected to the %s", n.label(), NodeTypeInput))
+			reached[n.ID] = true
+		}
+	}
+	return errs
+}
@@ -0,0 +1,100 @@
+package jdm
+
+import (
+	"strings"
+	"testing"
+)
+
+// node and edge JSON shorthands for the test documents
+const (
+	inputJSON  = `{"id":"in","type":"inputNode","name":"request"}`
+	outputJSON = `{"id":"out","type":"outputNode","name":"response"}`
+	exprJSON   = `{"id":"calc","type":"expressionNode","name":"calc","content":{"expressions":[{"id":"e1","key":"total","value":"price * 2"}]}}`
+)
+
+func doc(nodes, edges string) string {
+	return `{"nodes":[` + nodes + `],"edges":[` + edges + `]}`
+}
+
+func edge(id, from, to string) string {
+	return `{"id":"` + id + `","sourceId":"` + from + `","targetId":"` + to + `"}`
+}
+
+func TestValidate(t *testing.T) {
+	valid := doc(inputJSON+","+exprJSON+","+outputJSON, edge("e1", "in", "calc")+","+edge("e2", "calc", "out"))
+
+	for _, tc := range []struct {
+		name string
+		json string
+		want []string // substrings, one per expected message; nil = valid
+	}{
+		{"valid", valid, nil},
+		{"no nodes", `{"nodes":[],"edges":[]}`, []string{"document has no nodes"}},
+		{
+			"duplicate node id",
+			doc(inputJSON+","+outputJSON+`,{"id":"out","type":"outputNode"}`, edge("e1", "in", "out")),
+			[]string{`nodes[2]: duplicate node id "out"`},
+		},
+		{
+			"edge to unknown node",
+			doc(inputJSON+","+outputJSON, edge("e1", "in", "out")+","+edge("e2", "in", "ghost")),
+			[]string{`edge "e2": target "ghost" does not exist`},
+		},
+		{
+			"edge from unknown node",
+			doc(inputJSON+","+outputJSON, edge("e1", "in", "out")+","+edge("e2", "ghost", "out")),
+			[]string{`edge "e2": source "ghost" does not exist`},
+		},
+		{
+			"cycle",
+			doc(inputJSON+","+exprJSON+`,{"id":"calc2","type":"expressionNode","content":{"expressions":[]}},`+outputJSON,
+				edge("e1", "in", "calc")+","+edge("e2", "calc", "calc2")+","+edge("e3", "calc2", "calc")+","+edge("e4", "calc2", "out")),
+			[]string{`node "calc" (calc): is part of a cycle`, `node "calc2": is part of a cycle`},
+		},
+		{
+			"disconnected node",
+			doc(inputJSON+","+exprJSON+","+outputJSON, edge("e1", "in", "out")),
+			[]string{`node "calc" (calc): is not connected to the inputNode`},
+		},
+		{
+			"missing input node",
+			doc(exprJSON+","+outputJSON, edge("e1", "calc", "out")),
+			[]string{"document must have exactly one inputNode, found 0"},
+		},
+		{
+			"missing output node",
+			doc(inputJSON+","+exprJSON, edge("e1", "in", "calc")),
+			[]string{"document must have at least one outputNode"},
+		},
+		{
+			"unknown switch handle",
+			doc(inputJSON+`,{"id":"sw","type":"switchNode","content":{"statements":[{"id":"s1","condition":"a > 1"}]}},`+outputJSON,
+				edge("e1", "in", "sw")+`,{"id":"e2","sourceId":"sw","targetId":"out","sourceHandle":"s9"}`),
+			[]string{`edge "e2": switch "sw" has no statement "s9"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := Parse([]byte(tc.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Validate(d)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d messages %q, want %d", len(got), got, len(tc.want))
+			}
+			for i, want := range tc.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("message %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, raw := range []string{``, `{"nodes": [`, `[]`, `{"nodes": "x"}`} {
+		if _, err := Parse([]byte(raw)); err == nil || !strings.HasPrefix(err.Error(), "invalid JDM JSON") {
+			t.Errorf("Parse(%q) = %v, want an invalid JDM JSON error", raw, err)
+		}
+	}
+}
@@ -0,0 +1,1211 @@
+// Package mockserver is an in-process stand-in for the GoRules BRMS API used
+// by the provider tests. It keeps projects, environments, groups, documents,
+// deployments and access tokens in memory, answers with the same response
+// shapes as the real API (the simulator runs the embedded JDM engine), records
+// every request and can inject faults (5xx, 429, slow responses, redirects).
+//
+//	srv := mockserver.New()
+//	defer srv.Close()
+//	// provider "gorules" { base_url = srv.URL, token = srv.Token }
+package mockserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/jdm"
+)
+
+// DefaultToken is the bearer token accepted by a new Server
+const DefaultToken = "test-token"
+
+var reProjectKey = regexp.MustCompile(`^[a-z0-9]{2,}(-[a-z0-9]+)*$`)
+
+// -----------------------------------------------------------------------------
+// Stored objects
+// -----------------------------------------------------------------------------
+
+type Project struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+	Protected bool   `json:"protected"`
+}
+
+type Environment struct {
+	ID             string   `json:"id"`
+	ProjectID      string   `json:"projectId"`
+	Name           string   `json:"name"`
+	Key            string   `json:"key"`
+	Type           string   `json:"type"`
+	ApprovalMode   *string  `json:"approvalMode,omitempty"`
+	ApprovalGroups []string `json:"-"` // group IDs; rendered according to Options
+}
+
+type Group struct {
+	ID          string   `json:"id"`
+	ProjectID   string   `json:"-"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+type Document struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"-"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Content   json.RawMessage `json:"content,omitempty"`
+}
+
+// Deployment is a document released to an environment
+type Deployment struct {
+	ID            string `json:"id"`
+	ProjectID     string `json:"-"`
+	EnvironmentID string `json:"environmentId"`
+	DocumentID    string `json:"documentId"`
+}
+
+// AccessToken is a short-lived API token scoped to a project or one of its
+// environments
+type AccessToken struct {
+	ID            string    `json:"id"`
+	ProjectID     string    `json:"-"`
+	EnvironmentID string    `json:"environmentId,omitempty"`
+	Name          string    `json:"name"`
+	Token         string    `json:"-"` // answered once, by the create call
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// Request is a recorded API call
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// Fault makes matching requests fail before they reach the handlers
+type Fault struct {
+	Method     string        // "" matches any method
+	PathPrefix string        // "" matches any path
+	Status     int           // response status; 0 only applies Delay
+	Body       string        // response body for Status
+	RetryAfter string        // Retry-After header (429/503)
+	Location   string        // Location header (3xx); relative paths are resolved against the server URL
+	Delay      time.Duration // sleep before answering
+	Times      int           // number of requests affected; 0 = every request
+}
+
+// Options change the response shapes to cover the variants of the real API
+type Options struct {
+	FlatProjects            bool // answer projects as {...} instead of {"project": {...}}
+	ApprovalGroupsAsObjects bool // environments list approvalGroups as [{id,name}] instead of [id]
+	NullPermissions         bool // groups without permissions answer "permissions": null
+	GroupsPageSize          int  // max page size for group listings (default: perPage from the query)
+
+	ListingLag int // new environments/groups are missing from, and updated ones stale in, the next N listings
+
+	Version  string   // GET /api/version answers 404 when empty, like older releases
+	Features []string // features listed by GET /api/version
+}
+
+// -----------------------------------------------------------------------------
+// Server
+// -----------------------------------------------------------------------------
+
+type Server struct {
+	*httptest.Server
+	Token string
+
+	mu           sync.Mutex
+	opts         Options
+	seq          int
+	projects     map[string]*Project
+	projectOrder []string
+	environments map[string][]*Environment // by project ID, creation order
+	groups       map[string][]*Group       // by project ID, creation order
+	documents    map[string][]*Document    // by project ID, creation order
+	deployments  map[string][]*Deployment  // by project ID, creation order
+	accessTokens map[string][]*AccessToken // by project ID, creation order
+	lag          map[string]*lagged        // by object ID, see Options.ListingLag
+	faults       []*Fault
+	requests     []Request
+}
+
+// New starts a server with empty state
+func New(opts ...Options) *Server {
+	s := &Server{
+		Token:        DefaultToken,
+		projects:     map[string]*Project{},
+		environments: map[string][]*Environment{},
+		groups:       map[string][]*Group{},
+		documents:    map[string][]*Document{},
+		deployments:  map[string][]*Deployment{},
+		accessTokens: map[string][]*AccessToken{},
+		lag:          map[string]*lagged{},
+	}
+	if len(opts) > 0 {
+		s.opts = opts[0]
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/version", s.version)
+
+	mux.HandleFunc("GET /api/projects", s.listProjects)
+	mux.HandleFunc("POST /api/projects", s.createProject)
+	mux.HandleFunc("GET /api/projects/{id}", s.getProject)
+	mux.HandleFunc("PUT /api/projects/{id}", s.updateProject)
+	mux.HandleFunc("PUT /api/projects/{id}/{$}", s.updateProject) // canonical form behind some load balancers
+	mux.HandleFunc("DELETE /api/projects/{id}", s.deleteProject)
+
+	mux.HandleFunc("GET /api/projects/{id}/environments", s.listEnvironments)
+	mux.HandleFunc("POST /api/projects/{id}/environments", s.createEnvironment)
+	mux.HandleFunc("PUT /api/projects/{id}/environments/{envId}", s.updateEnvironment)
+	mux.HandleFunc("DELETE /api/projects/{id}/environments/{envId}", s.deleteEnvironment)
+
+	mux.HandleFunc("GET /api/projects/{id}/groups", s.listGroups)
+	mux.HandleFunc("POST /api/projects/{id}/groups", s.createGroup)
+	mux.HandleFunc("PUT /api/projects/{id}/groups/{groupId}", s.updateGroup)
+	mux.HandleFunc("DELETE /api/projects/{id}/groups/{groupId}", s.deleteGroup)
+
+	mux.HandleFunc("GET /api/projects/{id}/documents", s.listDocuments)
+	mux.HandleFunc("GET /api/projects/{id}/documents/{docId}", s.getDocument)
+	mux.HandleFunc("DELETE /api/projects/{id}/documents/{docId}", s.deleteDocument)
+	mux.HandleFunc("POST /api/projects/{id}/simulate", s.simulate)
+
+	mux.HandleFunc("GET /api/projects/{id}/deployments", s.listDeployments)
+	mux.HandleFunc("DELETE /api/projects/{id}/deployments/{deploymentId}", s.deleteDeployment)
+
+	mux.HandleFunc("POST /api/projects/{id}/access-tokens", s.createAccessToken)
+	mux.HandleFunc("DELETE /api/projects/{id}/access-tokens/{tokenId}", s.deleteAccessToken)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// SetOptions switches response shapes at runtime
+func (s *Server) SetOptions(o Options) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts = o
+}
+
+// SetToken changes the accepted token (e.g. to simulate a rotation)
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Token = token
+}
+
+// Inject adds a fault; faults are checked in insertion order
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every pending fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns a copy of the recorded requests
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Request, len(s.requests))
+	copy(out, s.requests)
+	return out
+}
+
+// CountRequests counts recorded requests by method and exact path
+func (s *Server) CountRequests(method, path string) int {
+	n := 0
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// ResetRequests clears the request log
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		fault := s.takeFault(r)
+		token := s.Token
+		s.mu.Unlock()
+
+		w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", len(s.Requests())))
+
+		if fault != nil {
+			if fault.Delay > 0 {
+				select {
+				case <-time.After(fault.Delay):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if fault.Status != 0 {
+				if fault.RetryAfter != "" {
+					w.Header().Set("Retry-After", fault.RetryAfter)
+				}
+				if fault.Location != "" {
+					loc := fault.Location
+					if strings.HasPrefix(loc, "/") {
+						loc = s.URL + loc
+					}
+					w.Header().Set("Location", loc)
+				}
+				w.WriteHeader(fault.Status)
+				_, _ = io.WriteString(w, fault.Body)
+				return
+			}
+		}
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer "+token && !s.validAccessToken(strings.TrimPrefix(auth, "Bearer ")) {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validAccessToken reports whether token was minted by the access-tokens
+// endpoints and is neither revoked nor expired (scopes are not enforced)
+func (s *Server) validAccessToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tokens := range s.accessTokens {
+		for _, t := range tokens {
+			if t.Token == token && time.Now().Before(t.ExpiresAt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// takeFault returns the first matching fault and consumes one use (mu held)
+func (s *Server) takeFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.PathPrefix != "" && !strings.HasPrefix(r.URL.Path, f.PathPrefix) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		cp := *f
+		return &cp
+	}
+	return nil
+}
+
+func (s *Server) nextID() string {
+	s.seq++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.seq, s.seq)
+}
+
+// -----------------------------------------------------------------------------
+// Direct state access (seeding and out-of-band changes in tests)
+// -----------------------------------------------------------------------------
+
+// AddProject stores p (assigning an ID if empty) and returns it
+func (s *Server) AddProject(p Project) Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.ID == "" {
+		p.ID = s.nextID()
+	}
+	s.projects[p.ID] = &p
+	s.projectOrder = append(s.projectOrder, p.ID)
+	return p
+}
+
+func (s *Server) Project(id string) (Project, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[id]
+	if !ok {
+		return Project{}, false
+	}
+	return *p, true
+}
+
+// Projects returns every project in creation order
+func (s *Server) Projects() []Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Project, 0, len(s.projectOrder))
+	for _, id := range s.projectOrder {
+		out = append(out, *s.projects[id])
+	}
+	return out
+}
+
+// UpdateProject applies fn to a stored project (out-of-band edit)
+func (s *Server) UpdateProject(id string, fn func(*Project)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[id]
+	if ok {
+		fn(p)
+	}
+	return ok
+}
+
+// RemoveProject deletes a project and its children (out-of-band delete)
+func (s *Server) RemoveProject(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeProjectLocked(id)
+}
+
+func (s *Server) removeProjectLocked(id string) {
+	delete(s.projects, id)
+	delete(s.environments, id)
+	delete(s.groups, id)
+	delete(s.documents, id)
+	delete(s.deployments, id)
+	delete(s.accessTokens, id)
+	for i, pid := range s.projectOrder {
+		if pid == id {
+			s.projectOrder = append(s.projectOrder[:i], s.projectOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *Server) AddEnvironment(e Environment) Environment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.ID == "" {
+		e.ID = s.nextID()
+	}
+	s.environments[e.ProjectID] = append(s.environments[e.ProjectID], &e)
+	return e
+}
+
+func (s *Server) Environments(projectID string) []Environment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Environment, 0, len(s.environments[projectID]))
+	for _, e := range s.environments[projectID] {
+		out = append(out, *e)
+	}
+	return out
+}
+
+func (s *Server) UpdateEnvironment(projectID, id string, fn func(*Environment)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e := s.findEnvironment(projectID, id); e != nil {
+		fn(e)
+		return true
+	}
+	return false
+}
+
+func (s *Server) RemoveEnvironment(projectID, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.environments[projectID] = removeByID(s.environments[projectID], id, func(e *Environment) string { return e.ID })
+}
+
+func (s *Server) AddGroup(g Group) Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g.ID == "" {
+		g.ID = s.nextID()
+	}
+	s.groups[g.ProjectID] = append(s.groups[g.ProjectID], &g)
+	return g
+}
+
+func (s *Server) Groups(projectID string) []Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Group, 0, len(s.groups[projectID]))
+	for _, g := range s.groups[projectID] {
+		out = append(out, *g)
+	}
+	return out
+}
+
+func (s *Server) UpdateGroup(projectID, id string, fn func(*Group)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g := s.findGroup(projectID, id); g != nil {
+		fn(g)
+		return true
+	}
+	return false
+}
+
+func (s *Server) RemoveGroup(projectID, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups[projectID] = removeByID(s.groups[projectID], id, func(g *Group) string { return g.ID })
+}
+
+func (s *Server) AddDocument(d Document) Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d.ID == "" {
+		d.ID = s.nextID()
+	}
+	s.documents[d.ProjectID] = append(s.documents[d.ProjectID], &d)
+	return d
+}
+
+func (s *Server) UpdateDocument(projectID, id string, fn func(*Document)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.documents[projectID] {
+		if d.ID == id {
+			fn(d)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) RemoveDocument(projectID, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents[projectID] = removeByID(s.documents[projectID], id, func(d *Document) string { return d.ID })
+}
+
+func (s *Server) Documents(projectID string) []Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Document, 0, len(s.documents[projectID]))
+	for _, d := range s.documents[projectID] {
+		out = append(out, *d)
+	}
+	return out
+}
+
+func (s *Server) AddDeployment(d Deployment) Deployment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d.ID == "" {
+		d.ID = s.nextID()
+	}
+	s.deployments[d.ProjectID] = append(s.deployments[d.ProjectID], &d)
+	return d
+}
+
+func (s *Server) Deployments(projectID string) []Deployment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Deployment, 0, len(s.deployments[projectID]))
+	for _, d := range s.deployments[projectID] {
+		out = append(out, *d)
+	}
+	return out
+}
+
+// AccessTokens returns the tokens of a project that have not been revoked
+func (s *Server) AccessTokens(projectID string) []AccessToken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]AccessToken, 0, len(s.accessTokens[projectID]))
+	for _, t := range s.accessTokens[projectID] {
+		out = append(out, *t)
+	}
+	return out
+}
+
+func (s *Server) findEnvironment(projectID, id string) *Environment {
+	for _, e := range s.environments[projectID] {
+		if e.ID == id {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *Server) findGroup(projectID, id string) *Group {
+	for _, g := range s.groups[projectID] {
+		if g.ID == id {
+			return g
+		}
+	}
+	return nil
+}
+
+// lagged is an object that listings do not show as it is yet
+type lagged struct {
+	remaining int // listings left
+	stale     any // value shown meanwhile; nil hides the object
+}
+
+// lagChange starts Options.ListingLag for an object (mu held); stale is its
+// value before an update, or nil for a new object
+func (s *Server) lagChange(id string, stale any) {
+	if s.opts.ListingLag > 0 {
+		s.lag[id] = &lagged{remaining: s.opts.ListingLag, stale: stale}
+	}
+}
+
+// listed returns what a listing shows for an object, and false while a new
+// object is not listed yet (mu held)
+func listed[T any](s *Server, id string, current T) (T, bool) {
+	l := s.lag[id]
+	if l == nil || l.remaining == 0 {
+		return current, true
+	}
+	l.remaining--
+	if l.stale == nil {
+		return current, false
+	}
+	return l.stale.(T), true
+}
+
+func removeByID[T any](xs []T, id string, idOf func(T) string) []T {
+	for i, x := range xs {
+		if idOf(x) == id {
+			return append(xs[:i], xs[i+1:]...)
+		}
+	}
+	return xs
+}
+
+// -----------------------------------------------------------------------------
+// Version
+// -----------------------------------------------------------------------------
+
+func (s *Server) version(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	version, features := s.opts.Version, s.opts.Features
+	s.mu.Unlock()
+	if version == "" {
+		writeError(w, http.StatusNotFound, "Cannot GET /api/version")
+		return
+	}
+	if features == nil {
+		features = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"version": version, "features": features})
+}
+
+// -----------------------------------------------------------------------------
+// Projects
+// -----------------------------------------------------------------------------
+
+type projectPayload struct {
+	Name           string `json:"name"`
+	Key            string `json:"key"`
+	Protected      *bool  `json:"protected"`
+	CopyContentRef string `json:"copyContentRef"`
+}
+
+func (s *Server) projectBody(p Project) any {
+	if s.opts.FlatProjects {
+		return p
+	}
+	return map[string]any{"project": p}
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.Projects())
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	var in projectPayload
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if !reProjectKey.MatchString(in.Key) {
+		writeError(w, http.StatusBadRequest, "key must match "+reProjectKey.String())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.projects {
+		if p.Key == in.Key {
+			writeError(w, http.StatusConflict, "project with key "+in.Key+" already exists")
+			return
+		}
+	}
+	if in.CopyContentRef != "" {
+		if _, ok := s.projects[in.CopyContentRef]; !ok {
+			writeError(w, http.StatusBadRequest, "copyContentRef project not found")
+			return
+		}
+	}
+	p := &Project{ID: s.nextID(), Name: in.Name, Key: in.Key}
+	if in.Protected != nil {
+		p.Protected = *in.Protected
+	}
+	s.projects[p.ID] = p
+	s.projectOrder = append(s.projectOrder, p.ID)
+
+	w.Header().Set("Location", "/api/projects/"+p.ID)
+	writeJSON(w, http.StatusCreated, s.projectBody(*p))
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.projectBody(*p))
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) {
+	var in projectPayload
+	if !decode(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	if in.Key != "" && !reProjectKey.MatchString(in.Key) {
+		writeError(w, http.StatusBadRequest, "key must match "+reProjectKey.String())
+		return
+	}
+	for _, other := range s.projects {
+		if other.ID != p.ID && in.Key != "" && other.Key == in.Key {
+			writeError(w, http.StatusConflict, "project with key "+in.Key+" already exists")
+			return
+		}
+	}
+	if in.Name != "" {
+		p.Name = in.Name
+	}
+	if in.Key != "" {
+		p.Key = in.Key
+	}
+	if in.Protected != nil {
+		p.Protected = *in.Protected
+	}
+	writeJSON(w, http.StatusOK, s.projectBody(*p))
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	if p.Protected {
+		writeError(w, http.StatusForbidden, "project is protected")
+		return
+	}
+	if len(s.documents[p.ID]) > 0 || len(s.deployments[p.ID]) > 0 {
+		writeError(w, http.StatusConflict, "project still has documents or deployments")
+		return
+	}
+	s.removeProjectLocked(p.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// -----------------------------------------------------------------------------
+// Environments (no GET by ID: the API only lists, as an array)
+// -----------------------------------------------------------------------------
+
+type environmentPayload struct {
+	Name           string   `json:"name"`
+	Key            *string  `json:"key"`
+	Type           string   `json:"type"`
+	ApprovalMode   *string  `json:"approvalMode"`
+	ApprovalGroups []string `json:"approvalGroups"`
+}
+
+func (s *Server) environmentBody(e Environment) map[string]any {
+	out := map[string]any{
+		"id":        e.ID,
+		"projectId": e.ProjectID,
+		"name":      e.Name,
+		"key":       e.Key,
+		"type":      e.Type,
+	}
+	if e.ApprovalMode != nil {
+		out["approvalMode"] = *e.ApprovalMode
+	}
+	if s.opts.ApprovalGroupsAsObjects {
+		objs := make([]map[string]string, 0, len(e.ApprovalGroups))
+		for _, id := range e.ApprovalGroups {
+			name := ""
+			if g := s.findGroup(e.ProjectID, id); g != nil {
+				name = g.Name
+			}
+			objs = append(objs, map[string]string{"id": id, "name": name})
+		}
+		out["approvalGroups"] = objs
+	} else {
+		ids := append([]string{}, e.ApprovalGroups...)
+		out["approvalGroups"] = ids
+	}
+	return out
+}
+
+func (s *Server) projectExists(w http.ResponseWriter, projectID string) bool {
+	if _, ok := s.projects[projectID]; !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return false
+	}
+	return true
+}
+
+func (s *Server) listEnvironments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid := r.PathValue("id")
+	if !s.projectExists(w, pid) {
+		return
+	}
+	out := make([]map[string]any, 0, len(s.environments[pid]))
+	for _, e := range s.environments[pid] {
+		if shown, ok := listed(s, e.ID, *e); ok {
+			out = append(out, s.environmentBody(shown))
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) applyEnvironment(w http.ResponseWriter, e *Environment, in environmentPayload) bool {
+	if in.Name == "" || in.Type == "" {
+		writeError(w, http.StatusBadRequest, "name and type are required")
+		return false
+	}
+	for _, gid := range in.ApprovalGroups {
+		if s.findGroup(e.ProjectID, gid) == nil {
+			writeError(w, http.StatusBadRequest, "approval group "+gid+" not found")
+			return false
+		}
+	}
+	e.Name = in.Name
+	e.Type = in.Type
+	e.Key = in.Name
+	if in.Key != nil && *in.Key != "" {
+		e.Key = *in.Key
+	}
+	e.ApprovalMode = in.ApprovalMode
+	e.ApprovalGroups = append([]string{}, in.ApprovalGroups...)
+	return true
+}
+
+func (s *Server) createEnvironment(w http.ResponseWriter, r *http.Request) {
+	var in environmentPayload
+	if !decode(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid := r.PathValue("id")
+	if !s.projectExists(w, pid) {
+		return
+	}
+	e := &Environment{ID: s.nextID(), ProjectID: pid}
+	if !s.applyEnvironment(w, e, in) {
+		return
+	}
+	for _, other := range s.environments[pid] {
+		if other.Key == e.Key {
+			writeError(w, http.StatusConflict, "environment with key "+e.Key+" already exists")
+			return
+		}
+	}
+	s.environments[pid] = append(s.environments[pid], e)
+	s.lagChange(e.ID, nil)
+	writeJSON(w, http.StatusCreated, s.environmentBody(*e))
+}
+
+func (s *Server) updateEnvironment(w http.ResponseWriter, r *http.Request) {
+	var in environmentPayload
+	if !decode(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.findEnvironment(r.PathValue("id"), r.PathValue("envId"))
+	if e == nil {
+		writeError(w, http.StatusNotFound, "environment not found")
+		return
+	}
+	next := *e
+	if !s.applyEnvironment(w, &next, in) {
+		return
+	}
+	s.lagChange(e.ID, *e)
+	*e = next
+	writeJSON(w, http.StatusOK, s.environmentBody(*e))
+}
+
+func (s *Server) deleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid, id := r.PathValue("id"), r.PathValue("envId")
+	if s.findEnvironment(pid, id) == nil {
+		writeError(w, http.StatusNotFound, "environment not found")
+		return
+	}
+	for _, d := range s.deployments[pid] {
+		if d.EnvironmentID == id {
+			writeError(w, http.StatusConflict, "environment has deployments")
+			return
+		}
+	}
+	s.environments[pid] = removeByID(s.environments[pid], id, func(e *Environment) string { return e.ID })
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// -----------------------------------------------------------------------------
+// Groups (list is paginated: {results, paginate})
+// -----------------------------------------------------------------------------
+
+type groupPayload struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func (s *Server) groupBody(g Group) Group {
+	if len(g.Permissions) == 0 {
+		if s.opts.NullPermissions {
+			g.Permissions = nil
+		} else {
+			g.Permissions = []string{}
+		}
+	}
+	return g
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid := r.PathValue("id")
+	if !s.projectExists(w, pid) {
+		return
+	}
+
+	all := make([]Group, 0, len(s.groups[pid]))
+	for _, g := range s.groups[pid] {
+		if shown, ok := listed(s, g.ID, *g); ok {
+			all = append(all, shown)
+		}
+	}
+	perPage := atoiDefault(r.URL.Query().Get("perPage"), 10)
+	if s.opts.GroupsPageSize > 0 && perPage > s.opts.GroupsPageSize {
+		perPage = s.opts.GroupsPageSize
+	}
+	page := atoiDefault(r.URL.Query().Get("page"), 1)
+	from := (page - 1) * perPage
+	if from > len(all) {
+		from = len(all)
+	}
+	to := from + perPage
+	if to > len(all) {
+		to = len(all)
+	}
+
+	results := make([]Group, 0, to-from)
+	for _, g := range all[from:to] {
+		results = append(results, s.groupBody(g))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"results": results,
+		"paginate": map[string]int{
+			"pageSize": perPage,
+			"current":  page,
+			"total":    len(all),
+			"from":     from + 1,
+			"to":       to,
+		},
+	})
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	var in groupPayload
+	if !decode(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid := r.PathValue("id")
+	if !s.projectExists(w, pid) {
+		return
+	}
+	if in.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	for _, other := range s.groups[pid] {
+		if other.Name == in.Name {
+			writeError(w, http.StatusConflict, "group "+in.Name+" already exists")
+			return
+		}
+	}
+	g := &Group{ID: s.nextID(), ProjectID: pid, Name: in.Name, Description: in.Description, Permissions: in.Permissions}
+	s.groups[pid] = append(s.groups[pid], g)
+	s.lagChange(g.ID, nil)
+	writeJSON(w, http.StatusCreated, s.groupBody(*g))
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	var in groupPayload
+	if !decode(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.findGroup(r.PathValue("id"), r.PathValue("groupId"))
+	if g == nil {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+	s.lagChange(g.ID, *g)
+	if in.Name != "" {
+		g.Name = in.Name
+	}
+	if in.Description != nil {
+		g.Description = in.Description
+	}
+	g.Permissions = in.Permissions
+	writeJSON(w, http.StatusOK, s.groupBody(*g))
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid, id := r.PathValue("id"), r.PathValue("groupId")
+	if s.findGroup(pid, id) == nil {
+		writeError(w, http.StatusNotFound, "group not found")
+		return
+	}
+	s.groups[pid] = removeByID(s.groups[pid], id, func(g *Group) string { return g.ID })
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// -----------------------------------------------------------------------------
+// Documents & deployments (lists are arrays; deployments pin their documents
+// and environments, which cannot be deleted while deployed)
+// -----------------------------------------------------------------------------
+
+func (s *Server) listDocuments(w http.ResponseWriter, r *http.Request) {
+	docs := s.Documents(r.PathValue("id"))
+	for i := range docs {
+		docs[i].Content = nil // listings omit the content
+	}
+	writeJSON(w, http.StatusOK, docs)
+}
+
+func (s *Server) getDocument(w http.ResponseWriter, r *http.Request) {
+	for _, d := range s.Documents(r.PathValue("id")) {
+		if d.ID == r.PathValue("docId") {
+			writeJSON(w, http.StatusOK, d)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "document not found")
+}
+
+func (s *Server) deleteDocument(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid, id := r.PathValue("id"), r.PathValue("docId")
+	for _, d := range s.deployments[pid] {
+		if d.DocumentID == id {
+			writeError(w, http.StatusConflict, "document is deployed")
+			return
+		}
+	}
+	before := len(s.documents[pid])
+	s.documents[pid] = removeByID(s.documents[pid], id, func(d *Document) string { return d.ID })
+	if len(s.documents[pid]) == before {
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// simulate runs the document with the embedded JDM engine, answering like
+// the BRMS simulator: {"result": {...}, "trace": {...}}
+func (s *Server) simulate(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Context any             `json:"context"`
+		Content json.RawMessage `json:"content"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	doc, err := jdm.Parse(in.Content)
+	if err == nil {
+		var out any
+		if out, err = jdm.Evaluate(doc, in.Context); err == nil {
+			writeJSON(w, http.StatusOK, map[string]any{"result": out, "trace": map[string]any{}})
+			return
+		}
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+func (s *Server) listDeployments(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Deployments(r.PathValue("id")))
+}
+
+func (s *Server) deleteDeployment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid, id := r.PathValue("id"), r.PathValue("deploymentId")
+	before := len(s.deployments[pid])
+	s.deployments[pid] = removeByID(s.deployments[pid], id, func(d *Deployment) string { return d.ID })
+	if len(s.deployments[pid]) == before {
+		writeError(w, http.StatusNotFound, "deployment not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// -----------------------------------------------------------------------------
+// Access tokens
+// -----------------------------------------------------------------------------
+
+type accessTokenPayload struct {
+	Name          string `json:"name"`
+	EnvironmentID string `json:"environmentId"`
+	TTLSeconds    int    `json:"ttlSeconds"`
+}
+
+func (s *Server) createAccessToken(w http.ResponseWriter, r *http.Request) {
+	var in accessTokenPayload
+	if !decode(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid := r.PathValue("id")
+	if !s.projectExists(w, pid) {
+		return
+	}
+	if in.Name == "" || in.TTLSeconds <= 0 {
+		writeError(w, http.StatusBadRequest, "name and ttlSeconds are required")
+		return
+	}
+	if in.EnvironmentID != "" && s.findEnvironment(pid, in.EnvironmentID) == nil {
+		writeError(w, http.StatusBadRequest, "environmentId "+in.EnvironmentID+" not found")
+		return
+	}
+	t := &AccessToken{
+		ID:            s.nextID(),
+		ProjectID:     pid,
+		EnvironmentID: in.EnvironmentID,
+		Name:          in.Name,
+		ExpiresAt:     time.Now().UTC().Add(time.Duration(in.TTLSeconds) * time.Second).Truncate(time.Second),
+	}
+	t.Token = "gat_" + strings.ReplaceAll(t.ID, "-", "")
+	s.accessTokens[pid] = append(s.accessTokens[pid], t)
+
+	body := map[string]any{"id": t.ID, "name": t.Name, "token": t.Token, "expiresAt": t.ExpiresAt}
+	if t.EnvironmentID != "" {
+		body["environmentId"] = t.EnvironmentID
+	}
+	writeJSON(w, http.StatusCreated, body)
+}
+
+func (s *Server) deleteAccessToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pid, id := r.PathValue("id"), r.PathValue("tokenId")
+	before := len(s.accessTokens[pid])
+	s.accessTokens[pid] = removeByID(s.accessTokens[pid], id, func(t *AccessToken) string { return t.ID })
+	if len(s.accessTokens[pid]) == before {
+		writeError(w, http.StatusNotFound, "access token not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with the BRMS error payload {statusCode, message, error}
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{
+		"statusCode": status,
+		"message":    msg,
+		"error":      http.StatusText(status),
+	})
+}
+
+func atoiDefault(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,189 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func do(t *testing.T, s *Server, method, path, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	return res, raw
+}
+
+func TestProjectEnvelope(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	res, raw := do(t, s, http.MethodPost, "/api/projects", `{"name":"Pricing","key":"pricing"}`)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("create: status=%d body=%s", res.StatusCode, raw)
+	}
+	var env struct {
+		Project Project `json:"project"`
+	}
+	if err := json.Unmarshal(raw, &env); err != nil || env.Project.ID == "" {
+		t.Fatalf("expected enveloped project, got %s", raw)
+	}
+
+	s.SetOptions(Options{FlatProjects: true})
+	_, raw = do(t, s, http.MethodGet, "/api/projects/"+env.Project.ID, "")
+	var flat Project
+	if err := json.Unmarshal(raw, &flat); err != nil || flat.Key != "pricing" {
+		t.Fatalf("expected flat project, got %s", raw)
+	}
+
+	if res, _ := do(t, s, http.MethodPost, "/api/projects", `{"name":"Dup","key":"pricing"}`); res.StatusCode != http.StatusConflict {
+		t.Fatalf("duplicate key: status=%d, want 409", res.StatusCode)
+	}
+}
+
+func TestDeploymentsPinChildren(t *testing.T) {
+	s := New()
+	defer s.Close()
+	p := s.AddProject(Project{Name: "P", Key: "pp"})
+	e := s.AddEnvironment(Environment{ProjectID: p.ID, Name: "prod", Key: "prod", Type: "deployment"})
+	d := s.AddDocument(Document{ProjectID: p.ID, Name: "pricing.json", Type: "decision"})
+	dep := s.AddDeployment(Deployment{ProjectID: p.ID, EnvironmentID: e.ID, DocumentID: d.ID})
+
+	for _, path := range []string{"/documents/" + d.ID, "/environments/" + e.ID, ""} {
+		if res, _ := do(t, s, http.MethodDelete, "/api/projects/"+p.ID+path, ""); res.StatusCode != http.StatusConflict {
+			t.Errorf("DELETE %s while deployed: status=%d, want 409", path, res.StatusCode)
+		}
+	}
+	for _, path := range []string{"/deployments/" + dep.ID, "/documents/" + d.ID, "/environments/" + e.ID, ""} {
+		if res, _ := do(t, s, http.MethodDelete, "/api/projects/"+p.ID+path, ""); res.StatusCode != http.StatusNoContent {
+			t.Errorf("DELETE %s: status=%d, want 204", path, res.StatusCode)
+		}
+	}
+}
+
+func TestListingLag(t *testing.T) {
+	s := New(Options{ListingLag: 1})
+	defer s.Close()
+	p := s.AddProject(Project{Name: "P", Key: "pp"})
+
+	res, raw := do(t, s, http.MethodPost, "/api/projects/"+p.ID+"/groups", `{"name":"editors","permissions":["read"]}`)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("create: status=%d body=%s", res.StatusCode, raw)
+	}
+	var g Group
+	_ = json.Unmarshal(raw, &g)
+	for i, want := range []string{`"results":[]`, `"name":"editors"`} {
+		if _, raw := do(t, s, http.MethodGet, "/api/projects/"+p.ID+"/groups", ""); !strings.Contains(string(raw), want) {
+			t.Errorf("listing %d after create: %s, want %s", i, raw, want)
+		}
+	}
+
+	do(t, s, http.MethodPut, "/api/projects/"+p.ID+"/groups/"+g.ID, `{"name":"writers","permissions":["read"]}`)
+	for i, want := range []string{`"name":"editors"`, `"name":"writers"`} {
+		if _, raw := do(t, s, http.MethodGet, "/api/projects/"+p.ID+"/groups", ""); !strings.Contains(string(raw), want) {
+			t.Errorf("listing %d after update: %s, want %s", i, raw, want)
+		}
+	}
+}
+
+func TestEnvironmentListingShapes(t *testing.T) {
+	s := New()
+	defer s.Close()
+	p := s.AddProject(Project{Name: "P", Key: "pp"})
+	g := s.AddGroup(Group{ProjectID: p.ID, Name: "approvers"})
+	s.AddEnvironment(Environment{ProjectID: p.ID, Name: "prod", Key: "prod", Type: "deployment", ApprovalGroups: []string{g.ID}})
+
+	_, raw := do(t, s, http.MethodGet, "/api/projects/"+p.ID+"/environments", "")
+	if !strings.Contains(string(raw), `"approvalGroups":["`+g.ID+`"]`) {
+		t.Fatalf("expected approvalGroups as IDs, got %s", raw)
+	}
+
+	s.SetOptions(Options{ApprovalGroupsAsObjects: true})
+	_, raw = do(t, s, http.MethodGet, "/api/projects/"+p.ID+"/environments", "")
+	if !strings.Contains(string(raw), `"name":"approvers"`) {
+		t.Fatalf("expected approvalGroups as objects, got %s", raw)
+	}
+}
+
+func TestGroupPagination(t *testing.T) {
+	s := New(Options{NullPermissions: true})
+	defer s.Close()
+	p := s.AddProject(Project{Name: "P", Key: "pp"})
+	for _, n := range []string{"a", "b", "c"} {
+		s.AddGroup(Group{ProjectID: p.ID, Name: n})
+	}
+
+	_, raw := do(t, s, http.MethodGet, "/api/projects/"+p.ID+"/groups?perPage=2&page=2", "")
+	var gl struct {
+		Results  []map[string]any `json:"results"`
+		Paginate struct {
+			Total int `json:"total"`
+		} `json:"paginate"`
+	}
+	if err := json.Unmarshal(raw, &gl); err != nil {
+		t.Fatal(err)
+	}
+	if len(gl.Results) != 1 || gl.Paginate.Total != 3 {
+		t.Fatalf("unexpected page: %s", raw)
+	}
+	if v, ok := gl.Results[0]["permissions"]; !ok || v != nil {
+		t.Fatalf("expected null permissions, got %s", raw)
+	}
+}
+
+func TestFaultsAndRecording(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	s.Inject(Fault{Method: http.MethodGet, PathPrefix: "/api/projects", Status: http.StatusTooManyRequests, RetryAfter: "1", Times: 1})
+	res, _ := do(t, s, http.MethodGet, "/api/projects", "")
+	if res.StatusCode != http.StatusTooManyRequests || res.Header.Get("Retry-After") != "1" {
+		t.Fatalf("expected 429 with Retry-After, got %d", res.StatusCode)
+	}
+	if res, _ := do(t, s, http.MethodGet, "/api/projects", ""); res.StatusCode != http.StatusOK {
+		t.Fatalf("fault should be consumed, got %d", res.StatusCode)
+	}
+
+	s.Inject(Fault{Status: http.StatusTemporaryRedirect, Location: "/api/projects/", Times: 1})
+	res, _ = do(t, s, http.MethodGet, "/api/projects", "")
+	if res.StatusCode != http.StatusTemporaryRedirect || res.Header.Get("Location") != s.URL+"/api/projects/" {
+		t.Fatalf("expected redirect, got %d %q", res.StatusCode, res.Header.Get("Location"))
+	}
+
+	s.Inject(Fault{Delay: 50 * time.Millisecond, Times: 1})
+	start := time.Now()
+	do(t, s, http.MethodGet, "/api/projects", "")
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatal("expected slow response")
+	}
+
+	if n := s.CountRequests(http.MethodGet, "/api/projects"); n != 4 {
+		t.Fatalf("recorded %d requests, want 4", n)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	s := New()
+	defer s.Close()
+	res, err := http.Get(s.URL + "/api/projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusUnauthorized || !strings.Contains(string(raw), `"statusCode":401`) {
+		t.Fatalf("expected 401 error payload, got %d %s", res.StatusCode, raw)
+	}
+}
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// -----------------------------------------------------------------------------
+// Authentication
+//
+// Exactly one credential source is configured: a static `token`, a
+// `token_file` re-read on every request, an `exec` credential helper, or
+// OAuth2 client credentials. authTransport sets the Authorization header from
+// it, so resources never handle tokens themselves.
+// -----------------------------------------------------------------------------
+
+// tokenSource yields the bearer token for a request
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// credentialError wraps failures to obtain a token
+type credentialError struct {
+	source string
+	err    error
+}
+
+func (e *credentialError) Error() string { return e.source + ": " + e.err.Error() }
+func (e *credentialError) Unwrap() error { return e.err }
+
+type authTransport struct {
+	next   http.RoundTripper
+	source tokenSource
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.next.RoundTrip(req)
+}
+
+// -----------------------------------------------------------------------------
+// Sources
+// -----------------------------------------------------------------------------
+
+type staticToken string
+
+func (s staticToken) Token(context.Context) (string, error) { return string(s), nil }
+
+// fileToken re-reads the file on every request, so rotated tokens are picked up
+type fileToken struct{ path string }
+
+func (f fileToken) Token(context.Context) (string, error) {
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", &credentialError{"token_file", err}
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", &credentialError{"token_file", fmt.Errorf("%s is empty", f.path)}
+	}
+	return token, nil
+}
+
+// execToken runs a credential helper. The helper prints either the token, or
+// JSON: {"token": "...", "expires_at": "<RFC 3339>"} or a Kubernetes-style
+// ExecCredential ({"status": {"token": "...", "expirationTimestamp": "..."}}).
+// The token is cached until shortly before it expires; tokens without an
+// expiry are cached for the rest of the run.
+type execToken struct {
+	command string
+	args    []string
+	env     map[string]string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// refresh this long before the reported expiry
+const tokenExpiryDelta = 30 * time.Second
+
+type execCredentialOutput struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Status    *struct {
+		Token               string    `json:"token"`
+		ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+	} `json:"status"`
+}
+
+func (e *execToken) Token(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" && (e.expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(e.expiry)) {
+		return e.token, nil
+	}
+
+	cmd := exec.CommandContext(ctx, e.command, e.args...)
+	cmd.Env = os.Environ()
+	for k, v := range e.env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", &credentialError{"exec " + e.command, err}
+	}
+
+	token, expiry, err := parseExecOutput(stdout.Bytes())
+	if err != nil {
+		return "", &credentialError{"exec " + e.command, err}
+	}
+	e.token, e.expiry = token, expiry
+	return token, nil
+}
+
+func parseExecOutput(out []byte) (string, time.Time, error) {
+	out = bytes.TrimSpace(out)
+	if jsonKind(out) != "object" {
+		if len(out) == 0 || bytes.ContainsAny(out, " \n\t") {
+			return "", time.Time{}, fmt.Errorf("expected a token or a JSON object on stdout")
+		}
+		return string(out), time.Time{}, nil
+	}
+	var o execCredentialOutput
+	if err := json.Unmarshal(out, &o); err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid JSON output: %w", err)
+	}
+	token, expiry := o.Token, o.ExpiresAt
+	if o.Status != nil && token == "" {
+		token, expiry = o.Status.Token, o.Status.ExpirationTimestamp
+	}
+	if token == "" {
+		return "", time.Time{}, fmt.Errorf("JSON output has no token")
+	}
+	return token, expiry, nil
+}
+
+// oauthToken uses the client-credentials grant; oauth2 caches the token and
+// refreshes it shortly before it expires
+type oauthToken struct{ ts oauth2.TokenSource }
+
+func (o oauthToken) Token(context.Context) (string, error) {
+	t, err := o.ts.Token()
+	if err != nil {
+		return "", &credentialError{"oauth", err}
+	}
+	return t.AccessToken, nil
+}
+
+// credentialPath is the provider attribute a source was configured with, so
+// rejected credentials are reported where the user set them
+func credentialPath(source tokenSource) path.Path {
+	switch source.(type) {
+	case staticToken:
+		return path.Root("token")
+	case fileToken:
+		return path.Root("token_file")
+	case *execToken:
+		return path.Root("exec")
+	case oauthToken:
+		return path.Root("oauth")
+	}
+	return path.Empty()
+}
+
+// -----------------------------------------------------------------------------
+// Configuration
+// -----------------------------------------------------------------------------
+
+type execModel struct {
+	Command types.String `tfsdk:"command"`
+	Args    types.List   `tfsdk:"args"`
+	Env     types.Map    `tfsdk:"env"`
+}
+
+type oauthModel struct {
+	TokenURL     types.String `tfsdk:"token_url"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Scopes       types.List   `tfsdk:"scopes"`
+	Audience     types.String `tfsdk:"audience"`
+}
+
+type authModel struct {
+	Token     types.String `tfsdk:"token"`
+	TokenFile types.String `tfsdk:"token_file"`
+	Exec      *execModel   `tfsdk:"exec"`
+	OAuth     *oauthModel  `tfsdk:"oauth"`
+}
+
+// buildTokenSource picks the configured credential source. httpClient is used
+// for OAuth token requests (it must not carry authTransport).
+func buildTokenSource(ctx context.Context, m authModel, httpClient *http.Client) (tokenSource, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var set []string
+	if stringSet(m.Token) {
+		set = append(set, "token")
+	}
+	if stringSet(m.TokenFile) {
+		set = append(set, "token_file")
+	}
+	if m.Exec != nil {
+		set = append(set, "exec")
+	}
+	if m.OAuth != nil {
+		set = append(set, "oauth")
+	}
+	switch len(set) {
+	case 0:
+		diags.AddError("Missing credentials",
+			"Configure one of `token`, `token_file`, `exec` or `oauth` in the provider block.")
+		return nil, diags
+	case 1:
+	default:
+		diags.AddError("Conflicting credentials",
+			fmt.Sprintf("Only one of `token`, `token_file`, `exec` or `oauth` can be set; got %s.", strings.Join(set, ", ")))
+		return nil, diags
+	}
+
+	switch set[0] {
+	case "token":
+		return staticToken(m.Token.ValueString()), diags
+
+	case "token_file":
+		src := fileToken{path: m.TokenFile.ValueString()}
+		if _, err := src.Token(ctx); err != nil {
+			diags.AddAttributeError(path.Root("token_file"), "Cannot read token_file", err.Error())
+		}
+		return src, diags
+
+	case "exec":
+		src := &execToken{command: m.Exec.Command.ValueString()}
+		diags.Append(m.Exec.Args.ElementsAs(ctx, &src.args, false)...)
+		diags.Append(m.Exec.Env.ElementsAs(ctx, &src.env, false)...)
+		if src.command == "" {
+			diags.AddAttributeError(path.Root("exec").AtName("command"), "Missing command", "exec.command is required.")
+		}
+		return src, diags
+
+	default: // oauth
+		cc := &clientcredentials.Config{
+			ClientID:     m.OAuth.ClientID.ValueString(),
+			ClientSecret: m.OAuth.ClientSecret.ValueString(),
+			TokenURL:     m.OAuth.TokenURL.ValueString(),
+		}
+		diags.Append(m.OAuth.Scopes.ElementsAs(ctx, &cc.Scopes, false)...)
+		if stringSet(m.OAuth.Audience) {
+			cc.EndpointParams = map[string][]string{"audience": {m.OAuth.Audience.ValueString()}}
+		}
+		for attr, v := range map[string]string{"token_url": cc.TokenURL, "client_id": cc.ClientID, "client_secret": cc.ClientSecret} {
+			if v == "" {
+				diags.AddAttributeError(path.Root("oauth").AtName(attr), "Missing OAuth setting", "oauth."+attr+" is required.")
+			}
+		}
+		// the token source outlives Configure, so it must not use its context
+		octx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+		return oauthToken{ts: cc.TokenSource(octx)}, diags
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFileTokenIsReread(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	src := fileToken{path: file}
+	ctx := context.Background()
+
+	for _, want := range []string{"first", "rotated"} {
+		if err := os.WriteFile(file, []byte(want+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := src.Token(ctx); err != nil || got != want {
+			t.Fatalf("Token() = %q, %v; want %q", got, err, want)
+		}
+	}
+
+	os.Remove(file)
+	var credErr *credentialError
+	if _, err := src.Token(ctx); !errors.As(err, &credErr) {
+		t.Fatalf("expected a credential error, got %v", err)
+	}
+}
+
+func TestExecToken(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	ctx := context.Background()
+
+	// every run appends to calls, so caching is observable
+	helper := func(output string) *execToken {
+		return &execToken{
+			command: "sh",
+			args:    []string{"-c", `echo x >> "$CALLS"; printf '%s' "$OUTPUT"`},
+			env:     map[string]string{"CALLS": calls, "OUTPUT": output},
+		}
+	}
+	runs := func() int {
+		b, _ := os.ReadFile(calls)
+		return strings.Count(string(b), "x")
+	}
+
+	src := helper("plain-token\n")
+	for i := 0; i < 2; i++ {
+		if got, err := src.Token(ctx); err != nil || got != "plain-token" {
+			t.Fatalf("Token() = %q, %v", got, err)
+		}
+	}
+	if runs() != 1 {
+		t.Fatalf("plain tokens must be cached, helper ran %d times", runs())
+	}
+
+	// a token about to expire is fetched again
+	soon, _ := json.Marshal(map[string]any{"token": "short-lived", "expires_at": time.Now().Add(10 * time.Second)})
+	src = helper(string(soon))
+	src.Token(ctx)
+	if got, _ := src.Token(ctx); got != "short-lived" || runs() != 3 {
+		t.Fatalf("expiring token: got %q after %d runs", got, runs())
+	}
+
+	kube := `{"kind":"ExecCredential","status":{"token":"kube-token","expirationTimestamp":"2099-01-01T00:00:00Z"}}`
+	if got, err := helper(kube).Token(ctx); err != nil || got != "kube-token" {
+		t.Fatalf("ExecCredential: %q, %v", got, err)
+	}
+
+	for _, bad := range []string{"", "two words", `{"expires_at":"2099-01-01T00:00:00Z"}`} {
+		if _, err := helper(bad).Token(ctx); err == nil {
+			t.Errorf("output %q: expected an error", bad)
+		}
+	}
+}
+
+func TestOAuthToken(t *testing.T) {
+	issued := 0
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("audience") != "gorules" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "terraform" || secret != "s3cr3t" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		// expires within the refresh window, so every call gets a new token
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": fmt.Sprintf("access-%d", issued), "token_type": "Bearer", "expires_in": 5})
+	}))
+	defer idp.Close()
+
+	m := authModel{OAuth: &oauthModel{
+		TokenURL:     types.StringValue(idp.URL),
+		ClientID:     types.StringValue("terraform"),
+		ClientSecret: types.StringValue("s3cr3t"),
+		Scopes:       types.ListNull(types.StringType),
+		Audience:     types.StringValue("gorules"),
+	}}
+	src, diags := buildTokenSource(context.Background(), m, http.DefaultClient)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	first, err := src.Token(context.Background())
+	if err != nil || first != "access-1" {
+		t.Fatalf("Token() = %q, %v", first, err)
+	}
+	if second, _ := src.Token(context.Background()); second != "access-2" {
+		t.Fatalf("expected a refreshed token, got %q", second)
+	}
+}
+
+func TestBuildTokenSourceChoice(t *testing.T) {
+	ctx := context.Background()
+	if _, diags := buildTokenSource(ctx, authModel{}, nil); !diags.HasError() {
+		t.Error("expected an error without credentials")
+	}
+	both := authModel{Token: types.StringValue("t"), TokenFile: types.StringValue("/tmp/token")}
+	if _, diags := buildTokenSource(ctx, both, nil); !diags.HasError() {
+		t.Error("expected an error with two credential sources")
+	}
+	src, diags := buildTokenSource(ctx, authModel{Token: types.StringValue("t")}, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got, _ := src.Token(ctx); got != "t" {
+		t.Errorf("static token = %q", got)
+	}
+}
+
+func TestCredentialPath(t *testing.T) {
+	for want, src := range map[string]tokenSource{
+		"token":      staticToken("t"),
+		"token_file": fileToken{path: "/tmp/token"},
+		"exec":       &execToken{command: "helper"},
+		"oauth":      oauthToken{},
+	} {
+		if got := credentialPath(src); !got.Equal(path.Root(want)) {
+			t.Errorf("credentialPath(%T) = %s, want %s", src, got, want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// -----------------------------------------------------------------------------
+// API errors → diagnostics
+//
+// Failed calls are turned into an *apiError (checkResponse) and reported with
+// addAPIError/addAPIWarning, which produce English, actionable diagnostics:
+// the summary names the operation and status, the detail carries the BRMS
+// message, guidance for the status and the request ID.
+// -----------------------------------------------------------------------------
+
+// BRMS error body: {"statusCode":409,"message":"...","error":"Conflict"}.
+// message is a string, or a list of strings for validation errors.
+type apiErrorPayload struct {
+	StatusCode int             `json:"statusCode"`
+	Message    json.RawMessage `json:"message"`
+	Error      string          `json:"error"`
+}
+
+type apiError struct {
+	Status    int
+	Messages  []string
+	RequestID string
+	Location  string // for redirects
+}
+
+// checkResponse returns nil for 2xx and an *apiError otherwise
+func checkResponse(res *http.Response, raw []byte) error {
+	if res.StatusCode < 300 {
+		return nil
+	}
+	e := &apiError{Status: res.StatusCode, Location: res.Header.Get("Location")}
+	for _, h := range requestIDHeaders {
+		if id := res.Header.Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+	e.Messages = apiErrorMessages(raw)
+	return e
+}
+
+// apiErrorMessages extracts the messages of a BRMS error body; other bodies
+// are returned as-is (trimmed) so nothing the server said is lost
+func apiErrorMessages(raw []byte) []string {
+	var p apiErrorPayload
+	if jsonKind(raw) == "object" && json.Unmarshal(raw, &p) == nil {
+		switch jsonKind(p.Message) {
+		case "string":
+			var msg string
+			if json.Unmarshal(p.Message, &msg) == nil && msg != "" {
+				return []string{msg}
+			}
+		case "array":
+			var msgs []string
+			if json.Unmarshal(p.Message, &msgs) == nil && len(msgs) > 0 {
+				return msgs
+			}
+		}
+		if p.Error != "" {
+			return []string{p.Error}
+		}
+	}
+	if body := strings.TrimSpace(string(raw)); body != "" {
+		const max = 512
+		if len(body) > max {
+			body = body[:max] + "..."
+		}
+		return []string{body}
+	}
+	return nil
+}
+
+func (e *apiError) Error() string {
+	s := fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	if len(e.Messages) > 0 {
+		s += ": " + strings.Join(e.Messages, "; ")
+	}
+	return s
+}
+
+func (e *apiError) guidance() string {
+	switch {
+	case e.Status == http.StatusUnauthorized:
+		return "The API rejected the credentials. Check that the token from the configured credential source (`token`, `token_file`, `exec` or `oauth`) is valid and has not expired or been revoked."
+	case e.Status == http.StatusForbidden:
+		return "The token is valid but not allowed to do this. Check the permissions of the token's user on the project, and whether the project is protected."
+	case e.Status == http.StatusNotFound:
+		return "The object was not found. It may have been deleted outside Terraform, or the ID or project_id is wrong."
+	case e.Status == http.StatusConflict:
+		return "An object with the same unique value already exists. Choose a different value, or bring the existing object under management with `terraform import`."
+	case e.Status == http.StatusTooManyRequests:
+		return "The API is rate limiting requests. Wait a moment and apply again."
+	case e.Status >= 500:
+		return "The GoRules API failed to process the request. Retry later; if it keeps failing, share the request ID with your GoRules administrator."
+	case e.Status >= 400:
+		return "The API rejected the request. Fix the value mentioned above and apply again."
+	case e.Status >= 300:
+		loc := e.Location
+		if loc == "" {
+			loc = "another location"
+		}
+		return fmt.Sprintf("The API redirected the request to %s. Redirects to another host, and redirects that would change a write into a GET, are not followed so the token and the request are not sent elsewhere. Set `base_url` to the final address of the API (scheme, host and path).", loc)
+	}
+	return ""
+}
+
+func (e *apiError) detail() string {
+	var b strings.Builder
+	if len(e.Messages) > 0 {
+		b.WriteString("The GoRules API answered: " + strings.Join(e.Messages, "; ") + "\n\n")
+	}
+	b.WriteString(e.guidance())
+	if e.RequestID != "" {
+		b.WriteString("\n\nRequest ID: " + e.RequestID)
+	}
+	return b.String()
+}
+
+// attribute returns the attribute whose API field is named in the messages
+func (e *apiError) attribute(fields map[string]path.Path) (path.Path, bool) {
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	for _, msg := range e.Messages {
+		for _, field := range names {
+			if regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(field) + `\b`).MatchString(msg) {
+				return fields[field], true
+			}
+		}
+	}
+	return path.Empty(), false
+}
+
+// addAPIError reports a failed API call. fields maps API field names (as they
+// appear in BRMS messages) to attributes, so validation errors point at the
+// offending attribute.
+func addAPIError(diags *diag.Diagnostics, action string, err error, fields map[string]path.Path) {
+	diags.Append(apiDiagnostic(diag.SeverityError, action, err, fields))
+}
+
+// addAPIWarning is addAPIError for calls whose failure does not stop the operation
+func addAPIWarning(diags *diag.Diagnostics, action string, err error) {
+	diags.Append(apiDiagnostic(diag.SeverityWarning, action, err, nil))
+}
+
+func apiDiagnostic(sev diag.Severity, action string, err error, fields map[string]path.Path) diag.Diagnostic {
+	summary, detail := action+" failed", err.Error()
+
+	var apiErr *apiError
+	var credErr *credentialError
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr):
+		summary = fmt.Sprintf("%s failed: %d %s", action, apiErr.Status, http.StatusText(apiErr.Status))
+		detail = apiErr.detail()
+	case errors.As(err, &credErr):
+		summary = action + " failed: no API token"
+		detail = credErr.Error() + "\n\nCould not obtain a token for the GoRules API. Check the provider `token_file`, `exec` or `oauth` settings."
+	case errors.As(err, &netErr) && netErr.Timeout():
+		detail = err.Error() + "\n\nThe request timed out. Check that `base_url` is reachable from this machine, or retry later."
+	case errors.As(err, &urlErr):
+		detail = err.Error() + "\n\nCould not reach the GoRules API. Check `base_url` and your network or proxy settings."
+	}
+
+	var d diag.Diagnostic
+	if sev == diag.SeverityWarning {
+		d = diag.NewWarningDiagnostic(summary, detail)
+	} else {
+		d = diag.NewErrorDiagnostic(summary, detail)
+	}
+	if apiErr != nil {
+		if p, ok := apiErr.attribute(fields); ok {
+			d = diag.WithPath(p, d)
+		}
+	}
+	return d
+}
+
+// isNotFound reports whether err is an API 404
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// API field names → attributes, for addAPIError
+var (
+	projectFields = map[string]path.Path{
+		"name":           path.Root("name"),
+		"key":            path.Root("key"),
+		"protected":      path.Root("protected"),
+		"copyContentRef": path.Root("copy_content_ref"),
+	}
+	environmentFields = map[string]path.Path{
+		"name":           path.Root("name"),
+		"key":            path.Root("key"),
+		"type":           path.Root("type"),
+		"approvalMode":   path.Root("approval_mode"),
+		"approvalGroups": path.Root("approval_groups"),
+	}
+	groupFields = map[string]path.Path{
+		"name":        path.Root("name"),
+		"description": path.Root("description"),
+		"permissions": path.Root("permissions"),
+	}
+)
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func testResponse(status int, body string) (*http.Response, []byte) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-Id", "req-42")
+	rec.WriteHeader(status)
+	_, _ = rec.WriteString(body)
+	return rec.Result(), []byte(body)
+}
+
+func TestCheckResponse(t *testing.T) {
+	cases := []struct {
+		status   int
+		body     string
+		messages []string
+	}{
+		{http.StatusConflict, `{"statusCode":409,"message":"project with key pricing already exists","error":"Conflict"}`, []string{"project with key pricing already exists"}},
+		{http.StatusBadRequest, `{"statusCode":400,"message":["name should not be empty","key must match ^[a-z]+$"],"error":"Bad Request"}`, []string{"name should not be empty", "key must match ^[a-z]+$"}},
+		{http.StatusForbidden, `{"statusCode":403,"error":"Forbidden"}`, []string{"Forbidden"}},
+		{http.StatusBadGateway, `<html>bad gateway</html>`, []string{"<html>bad gateway</html>"}},
+		{http.StatusNotFound, ``, nil},
+	}
+	for _, tc := range cases {
+		res, raw := testResponse(tc.status, tc.body)
+		err := checkResponse(res, raw)
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%d: expected *apiError, got %v", tc.status, err)
+		}
+		if apiErr.Status != tc.status || apiErr.RequestID != "req-42" {
+			t.Errorf("%d: got status=%d request_id=%q", tc.status, apiErr.Status, apiErr.RequestID)
+		}
+		if fmt.Sprint(apiErr.Messages) != fmt.Sprint(tc.messages) {
+			t.Errorf("%d: messages = %q, want %q", tc.status, apiErr.Messages, tc.messages)
+		}
+	}
+
+	if res, raw := testResponse(http.StatusOK, `{}`); checkResponse(res, raw) != nil {
+		t.Error("2xx must not be an error")
+	}
+}
+
+func TestAddAPIError(t *testing.T) {
+	res, raw := testResponse(http.StatusConflict, `{"statusCode":409,"message":"project with key pricing already exists","error":"Conflict"}`)
+	var diags diag.Diagnostics
+	addAPIError(&diags, "Create Project", checkResponse(res, raw), projectFields)
+
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+	d := diags[0]
+	if d.Summary() != "Create Project failed: 409 Conflict" {
+		t.Errorf("summary = %q", d.Summary())
+	}
+	for _, want := range []string{"already exists", "terraform import", "Request ID: req-42"} {
+		if !strings.Contains(d.Detail(), want) {
+			t.Errorf("detail missing %q: %s", want, d.Detail())
+		}
+	}
+	withPath, ok := d.(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("key")) {
+		t.Errorf("expected diagnostic on key, got %#v", d)
+	}
+
+	for status, want := range map[int]string{
+		http.StatusUnauthorized: "configured credential source",
+		http.StatusForbidden:    "not allowed",
+		http.StatusNotFound:     "deleted outside Terraform",
+		http.StatusBadGateway:   "Retry later",
+	} {
+		res, raw := testResponse(status, ``)
+		var diags diag.Diagnostics
+		addAPIError(&diags, "Read Project", checkResponse(res, raw), nil)
+		if !strings.Contains(diags[0].Detail(), want) {
+			t.Errorf("%d: detail %q does not contain %q", status, diags[0].Detail(), want)
+		}
+	}
+}
+
+func TestAddAPIErrorUnreachable(t *testing.T) {
+	_, err := http.Get("http://127.0.0.1:1/api/projects")
+	var diags diag.Diagnostics
+	addAPIError(&diags, "Read Project", err, nil)
+	if !strings.Contains(diags[0].Detail(), "Check `base_url`") {
+		t.Errorf("unexpected detail: %s", diags[0].Detail())
+	}
+}
@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	eschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// -----------------------------------------------------------------------------
+// Ephemeral resource: gorules_access_token
+//
+// Mints a short-lived API token scoped to a project (or one environment) when
+// Terraform opens it, and revokes it when Terraform closes it at the end of
+// the plan/apply. The token only lives in memory: it is never written to state
+// or plan files, so it can only feed other ephemeral contexts (provider
+// blocks, write-only attributes, other ephemeral resources).
+// -----------------------------------------------------------------------------
+
+const (
+	defaultAccessTokenName = "terraform"
+	defaultAccessTokenTTL  = time.Hour
+	minAccessTokenTTL      = time.Minute
+	maxAccessTokenTTL      = 24 * time.Hour
+
+	accessTokenPrivateKey = "access_token" // private data: what Close revokes
+)
+
+type accessTokenEphemeral struct{ cfg *Config }
+
+type accessTokenModel struct {
+	ProjectID     types.String `tfsdk:"project_id"`
+	EnvironmentID types.String `tfsdk:"environment_id"`
+	Name          types.String `tfsdk:"name"`
+	TTL           types.String `tfsdk:"ttl"`
+	ID            types.String `tfsdk:"id"`
+	Token         types.String `tfsdk:"token"`
+	ExpiresAt     types.String `tfsdk:"expires_at"`
+}
+
+type accessTokenRequest struct {
+	Name          string `json:"name"`
+	EnvironmentID string `json:"environmentId,omitempty"`
+	TTLSeconds    int64  `json:"ttlSeconds"`
+}
+
+type accessTokenItem struct {
+	ID        string    `json:"id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// accessTokenRef is stored in private data between Open and Close
+type accessTokenRef struct {
+	ProjectID string `json:"project_id"`
+	ID        string `json:"id"`
+}
+
+var accessTokenFields = map[string]path.Path{
+	"name":          path.Root("name"),
+	"environmentId": path.Root("environment_id"),
+	"ttlSeconds":    path.Root("ttl"),
+}
+
+func NewAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &accessTokenEphemeral{}
+}
+
+func (e *accessTokenEphemeral) Metadata(_ context.Context, _ ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = "gorules_access_token"
+}
+
+func (e *accessTokenEphemeral) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = eschema.Schema{
+		MarkdownDescription: "Short-lived GoRules API token scoped to a project or environment. The token is minted during plan/apply, revoked when Terraform is done with it, and never stored in state or plan files.",
+		Attributes: map[string]eschema.Attribute{
+			"project_id": eschema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Project the token gives access to.",
+			},
+			"environment_id": eschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Restrict the token to this environment of the project.",
+			},
+			"name": eschema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Name shown for the token in the BRMS. Default: `terraform`.",
+			},
+			"ttl": eschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Lifetime of the token, between `1m` and `24h` (e.g. `30m`). Default: `1h`. The token is revoked earlier, when Terraform closes the ephemeral resource.",
+			},
+			"id": eschema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Token ID.",
+			},
+			"token": eschema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The token, sent as `Authorization: Bearer <token>`.",
+			},
+			"expires_at": eschema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Expiry time (RFC 3339).",
+			},
+		},
+	}
+}
+
+func (e *accessTokenEphemeral) Configure(_ context.Context, req ephemeral.ConfigureRequest, _ *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	e.cfg = req.ProviderData.(*Config)
+}
+
+func (e *accessTokenEphemeral) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var ttl types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ttl"), &ttl)...)
+	if ttl.IsNull() || ttl.IsUnknown() {
+		return
+	}
+	if _, err := accessTokenTTL(ttl); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid ttl", err.Error())
+	}
+}
+
+// accessTokenTTL parses ttl, defaulting to one hour
+func accessTokenTTL(ttl types.String) (time.Duration, error) {
+	if ttl.IsNull() || ttl.ValueString() == "" {
+		return defaultAccessTokenTTL, nil
+	}
+	d, err := time.ParseDuration(ttl.ValueString())
+	if err != nil {
+		return 0, err
+	}
+	if d < minAccessTokenTTL || d > maxAccessTokenTTL {
+		return 0, fmt.Errorf("ttl must be between %s and %s, got %s", minAccessTokenTTL, maxAccessTokenTTL, d)
+	}
+	return d, nil
+}
+
+func (e *accessTokenEphemeral) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	if e.cfg == nil {
+		resp.Diagnostics.AddError("provider not configured", "Missing base_url/token")
+		return
+	}
+	if !e.cfg.Server.Supports(FeatureAccessTokens) {
+		resp.Diagnostics.AddError("Access tokens not supported",
+			fmt.Sprintf("This BRMS (version %q) cannot mint access tokens. Use a token created in the BRMS instead.", e.cfg.Server.Version))
+		return
+	}
+
+	var data accessTokenModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ttl, err := accessTokenTTL(data.TTL)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid ttl", err.Error())
+		return
+	}
+	if data.Name.IsNull() || data.Name.ValueString() == "" {
+		data.Name = types.StringValue(defaultAccessTokenName)
+	}
+
+	b, _ := json.Marshal(accessTokenRequest{
+		Name:          data.Name.ValueString(),
+		EnvironmentID: data.EnvironmentID.ValueString(),
+		TTLSeconds:    int64(ttl / time.Second),
+	})
+	url := fmt.Sprintf("%s/api/projects/%s/access-tokens", e.cfg.BaseURL, data.ProjectID.ValueString())
+	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	res, err := apiClient(e.cfg.HTTP).Do(httpReq)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Create Access Token", err, nil)
+		return
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	if err := checkResponse(res, raw); err != nil {
+		addAPIError(&resp.Diagnostics, "Create Access Token", err, accessTokenFields)
+		return
+	}
+
+	var created accessTokenItem
+	if err := json.Unmarshal(raw, &created); err != nil || created.ID == "" || created.Token == "" {
+		resp.Diagnostics.AddError("Error parsing Create Access Token response", "The response has no id or token.")
+		return
+	}
+
+	// record what to revoke first, so Close runs even if setting the result fails
+	ref, _ := json.Marshal(accessTokenRef{ProjectID: data.ProjectID.ValueString(), ID: created.ID})
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, accessTokenPrivateKey, ref)...)
+
+	data.ID = types.StringValue(created.ID)
+	data.Token = types.StringValue(created.Token)
+	data.ExpiresAt = types.StringValue(created.ExpiresAt.UTC().Format(time.RFC3339))
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Close revokes the token. Failures are warnings: the token still expires at
+// expires_at.
+func (e *accessTokenEphemeral) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	raw, diags := req.Private.GetKey(ctx, accessTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if len(raw) == 0 {
+		return
+	}
+	var ref accessTokenRef
+	if err := json.Unmarshal(raw, &ref); err != nil {
+		resp.Diagnostics.AddWarning("Revoke Access Token failed", err.Error())
+		return
+	}
+	if e.cfg == nil {
+		resp.Diagnostics.AddWarning("Revoke Access Token failed",
+			fmt.Sprintf("The provider is not configured; access token %s stays valid until it expires.", ref.ID))
+		return
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/access-tokens/%s", e.cfg.BaseURL, ref.ProjectID, ref.ID)
+	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	res, err := apiClient(e.cfg.HTTP).Do(httpReq)
+	if err == nil {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		err = checkResponse(res, body)
+	}
+	switch {
+	case isNotFound(err):
+		tflog.Debug(ctx, "access token already revoked or expired", map[string]interface{}{"id": ref.ID})
+	case err != nil:
+		addAPIWarning(&resp.Diagnostics, "Revoke Access Token", err)
+	}
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+// the echo provider copies ephemeral.gorules_access_token.test into
+// echo.test.data, so checks can see values that are otherwise never stored
+var testAccEphemeralFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"gorules": testAccProtoV6ProviderFactories["gorules"],
+	"echo":    echoprovider.NewProviderServer(),
+}
+
+func testAccAccessTokenConfig(srv *mockserver.Server, body string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "gorules_project" "test" {
+  name = "Token Tests"
+  key  = "token-tests"
+}
+
+resource "gorules_environment" "test" {
+  project_id = gorules_project.test.id
+  name       = "production"
+  type       = "brms"
+}
+
+ephemeral "gorules_access_token" "test" {
+  project_id = gorules_project.test.id
+  %s
+}
+
+provider "echo" {
+  data = ephemeral.gorules_access_token.test
+}
+
+resource "echo" "test" {}
+`, body)
+}
+
+func TestAccAccessToken_basic(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccEphemeralFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccessTokenConfig(srv, `environment_id = gorules_environment.test.id
+  ttl            = "15m"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("echo.test", "data.token", regexp.MustCompile(`^gat_`)),
+					resource.TestCheckResourceAttrPair("echo.test", "data.environment_id", "gorules_environment.test", "id"),
+					resource.TestCheckResourceAttr("echo.test", "data.name", "terraform"),
+					resource.TestCheckResourceAttrSet("echo.test", "data.expires_at"),
+					testAccCheckAccessTokensRevoked(srv),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckAccessTokensRevoked checks that every minted token was revoked
+// when Terraform closed the ephemeral resource, and that the request asked
+// for the configured TTL
+func testAccCheckAccessTokensRevoked(srv *mockserver.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		pid := s.RootModule().Resources["gorules_project.test"].Primary.ID
+		if left := srv.AccessTokens(pid); len(left) > 0 {
+			return fmt.Errorf("%d access tokens not revoked", len(left))
+		}
+		minted := 0
+		for _, r := range srv.Requests() {
+			if r.Method == "POST" && r.Path == "/api/projects/"+pid+"/access-tokens" {
+				minted++
+				if !strings.Contains(string(r.Body), `"ttlSeconds":900`) {
+					return fmt.Errorf("unexpected request body %s", r.Body)
+				}
+			}
+		}
+		if minted == 0 {
+			return fmt.Errorf("no access token was minted")
+		}
+		return nil
+	}
+}
+
+func TestAccAccessToken_validation(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccEphemeralFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAccessTokenConfig(srv, `ttl = "48h"`),
+				ExpectError: regexp.MustCompile(`ttl must be between 1m0s and 24h0m0s`),
+			},
+		},
+	})
+}
+
+func TestAccAccessToken_unsupported(t *testing.T) {
+	// the server publishes its features and access tokens are not among them
+	srv := testAccServer(t, mockserver.Options{Version: "1.30.0", Features: []string{FeatureSimulate}})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccEphemeralFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAccessTokenConfig(srv, ``),
+				ExpectError: regexp.MustCompile(`Access tokens not supported`),
+			},
+		},
+	})
+}
@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/jdm"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// -----------------------------------------------------------------------------
+// Function: provider::gorules::evaluate(jdm, input)
+// -----------------------------------------------------------------------------
+
+type evaluateFunction struct{}
+
+func NewEvaluateFunction() function.Function { return &evaluateFunction{} }
+
+func (f *evaluateFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "evaluate"
+}
+
+func (f *evaluateFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Evaluates a JDM decision graph locally.",
+		MarkdownDescription: "Runs a GoRules JSON Decision Model (JDM) document against `input` without contacting the BRMS " +
+			"and returns the output object. Supports decision tables (`first`/`collect` hit policies), expression and switch nodes; " +
+			"function nodes are not supported.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "jdm",
+				MarkdownDescription: "JDM document as a JSON string (e.g. `file(\"pricing.json\")`).",
+			},
+			function.DynamicParameter{
+				Name:                "input",
+				MarkdownDescription: "Input context, usually an object (e.g. `{ cart = { total = 150 } }`).",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *evaluateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var raw string
+	var input types.Dynamic
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &raw, &input))
+	if resp.Error != nil {
+		return
+	}
+
+	doc, err := jdm.Parse([]byte(raw))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	in, err := attrToGo(input)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	out, err := jdm.Evaluate(doc, in)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	result, err := goToAttr(out)
+	if err != nil {
+		// the graph produced it, not the caller's input
+		resp.Error = function.NewFuncError("decision result could not be converted: " + err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, types.DynamicValue(result)))
+}
+
+// -----------------------------------------------------------------------------
+// Terraform values <-> decoded JSON (map[string]any, []any, float64, ...)
+// -----------------------------------------------------------------------------
+
+func attrToGo(v attr.Value) (any, error) {
+	if v == nil || v.IsNull() {
+		return nil, nil
+	}
+	if v.IsUnknown() {
+		return nil, fmt.Errorf("value is not known yet")
+	}
+
+	switch x := v.(type) {
+	case basetypes.DynamicValue:
+		return attrToGo(x.UnderlyingValue())
+	case basetypes.StringValue:
+		return x.ValueString(), nil
+	case basetypes.BoolValue:
+		return x.ValueBool(), nil
+	case basetypes.NumberValue:
+		f, _ := x.ValueBigFloat().Float64()
+		return f, nil
+	case basetypes.Int64Value:
+		return float64(x.ValueInt64()), nil
+	case basetypes.Float64Value:
+		return x.ValueFloat64(), nil
+	case basetypes.ObjectValue:
+		return attrMapToGo(x.Attributes())
+	case basetypes.MapValue:
+		return attrMapToGo(x.Elements())
+	case basetypes.ListValue:
+		return attrSliceToGo(x.Elements())
+	case basetypes.SetValue:
+		return attrSliceToGo(x.Elements())
+	case basetypes.TupleValue:
+		return attrSliceToGo(x.Elements())
+	}
+	return nil, fmt.Errorf("unsupported value type %s", v.Type(context.Background()))
+}
+
+func attrMapToGo(in map[string]attr.Value) (any, error) {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		gv, err := attrToGo(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		out[k] = gv
+	}
+	return out, nil
+}
+
+func attrSliceToGo(in []attr.Value) (any, error) {
+	out := make([]any, 0, len(in))
+	for i, v := range in {
+		gv, err := attrToGo(v)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		out = append(out, gv)
+	}
+	return out, nil
+}
+
+// goToAttr builds objects and tuples so every element keeps its own type
+func goToAttr(v any) (attr.Value, error) {
+	switch x := v.(type) {
+	case nil:
+		return types.StringNull(), nil
+	case bool:
+		return types.BoolValue(x), nil
+	case float64:
+		// big.NewFloat panics on NaN
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, fmt.Errorf("result %v is not a finite number", x)
+		}
+		return types.NumberValue(big.NewFloat(x)), nil
+	case string:
+		return types.StringValue(x), nil
+	case []any:
+		elems := make([]attr.Value, 0, len(x))
+		elemTypes := make([]attr.Type, 0, len(x))
+		for _, it := range x {
+			av, err := goToAttr(it)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, av)
+			elemTypes = append(elemTypes, av.Type(context.Background()))
+		}
+		tv, diags := types.TupleValue(elemTypes, elems)
+		if diags.HasError() {
+			return nil, fmt.Errorf("building tuple: %v", diags)
+		}
+		return tv, nil
+	case map[string]any:
+		attrs := make(map[string]attr.Value, len(x))
+		attrTypes := make(map[string]attr.Type, len(x))
+		for k, it := range x {
+			av, err := goToAttr(it)
+			if err != nil {
+				return nil, err
+			}
+			attrs[k] = av
+			attrTypes[k] = av.Type(context.Background())
+		}
+		ov, diags := types.ObjectValue(attrTypes, attrs)
+		if diags.HasError() {
+			return nil, fmt.Errorf("building object: %v", diags)
+		}
+		return ov, nil
+	}
+	return nil, fmt.Errorf("unsupported result type %T", v)
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testEvaluateJDM = `{"nodes":[
+	{"id":"in","type":"inputNode"},
+	{"id":"x","type":"expressionNode","content":{"expressions":[{"id":"a","key":"root","value":"n ^ 0.5"}]}},
+	{"id":"out","type":"outputNode"}
+],"edges":[{"id":"e1","sourceId":"in","targetId":"x"},{"id":"e2","sourceId":"x","targetId":"out"}]}`
+
+func TestEvaluateFunction(t *testing.T) {
+	input := func(n int64) attr.Value {
+		return types.DynamicValue(types.ObjectValueMust(map[string]attr.Type{"n": types.NumberType},
+			map[string]attr.Value{"n": types.NumberValue(big.NewFloat(float64(n)))}))
+	}
+
+	got, ferr := testRunFunction(t, NewEvaluateFunction(), types.StringValue(testEvaluateJDM), input(16))
+	if ferr != nil {
+		t.Fatalf("function error: %s", ferr)
+	}
+	out, _ := got.(types.Dynamic).UnderlyingValue().(types.Object)
+	if root, _ := out.Attributes()["root"].(types.Number); root.ValueBigFloat().String() != "4" {
+		t.Errorf("got %v, want root = 4", got)
+	}
+
+	// the square root of a negative number is NaN, which must not panic
+	_, ferr = testRunFunction(t, NewEvaluateFunction(), types.StringValue(testEvaluateJDM), input(-8))
+	if ferr == nil || !strings.Contains(ferr.Text, "is not a finite number") {
+		t.Errorf("got %v, want a not a finite number error", ferr)
+	}
+}
+
+func TestGoToAttrNonFinite(t *testing.T) {
+	for _, v := range []any{math.NaN(), math.Inf(1), map[string]any{"x": []any{math.Inf(-1)}}} {
+		if _, err := goToAttr(v); err == nil || !strings.Contains(err.Error(), "is not a finite number") {
+			t.Errorf("goToAttr(%v) = %v, want a not a finite number error", v, err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// -----------------------------------------------------------------------------
+// Function: provider::gorules::key_from_name(name)
+// -----------------------------------------------------------------------------
+
+type keyFromNameFunction struct{}
+
+func NewKeyFromNameFunction() function.Function { return &keyFromNameFunction{} }
+
+func (f *keyFromNameFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "key_from_name"
+}
+
+func (f *keyFromNameFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Derives a valid project key from a name.",
+		MarkdownDescription: "Normalizes a display name into a project key matching `^[a-z0-9]{2,}(-[a-z0-9]+)*$`: " +
+			"accented characters are transliterated to ASCII, everything is lowercased, other characters become dashes " +
+			"and repeated dashes are collapsed. This is the same key `gorules_project` computes when `key` is omitted.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Display name, e.g. `\"Tarificación Europa\"`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *keyFromNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	key, err := projectKeyFromName(name)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, key))
+}
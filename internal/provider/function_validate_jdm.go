@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/jdm"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// -----------------------------------------------------------------------------
+// Function: provider::gorules::validate_jdm(json)
+// -----------------------------------------------------------------------------
+
+type validateJDMFunction struct{}
+
+func NewValidateJDMFunction() function.Function { return &validateJDMFunction{} }
+
+func (f *validateJDMFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_jdm"
+}
+
+func (f *validateJDMFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates a JDM decision graph offline.",
+		MarkdownDescription: "Parses a GoRules JSON Decision Model (JDM) document and checks node types, " +
+			"duplicate IDs, edge references, cycles and nodes not connected to the input node. " +
+			"Returns the list of problems found; an empty list means the document is valid. No network access is needed.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "json",
+				MarkdownDescription: "JDM document as a JSON string (e.g. `file(\"pricing.json\")`).",
+			},
+		},
+		Return: function.ListReturn{ElementType: types.StringType},
+	}
+}
+
+func (f *validateJDMFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var raw string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &raw))
+	if resp.Error != nil {
+		return
+	}
+
+	// malformed JSON is reported as a validation error, not a function error,
+	// so callers can always check length(...) == 0
+	var errs []string
+	doc, err := jdm.Parse([]byte(raw))
+	if err != nil {
+		errs = []string{err.Error()}
+	} else {
+		errs = jdm.Validate(doc)
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, ToTFStringList(errs)))
+}
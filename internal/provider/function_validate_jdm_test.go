@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testRunFunction calls f with args and returns the result value and the
+// function error, if any
+func testRunFunction(t *testing.T, f function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+	var def function.DefinitionResponse
+	f.Definition(ctx, function.DefinitionRequest{}, &def)
+	result, ferr := def.Definition.Return.NewResultData(ctx)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	resp := function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, &resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func TestValidateJDMFunction(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		json string
+		want string // substring of the single message; "" = valid
+	}{
+		{
+			"valid",
+			`{"nodes":[{"id":"in","type":"inputNode"},{"id":"out","type":"outputNode"}],"edges":[{"id":"e1","sourceId":"in","targetId":"out"}]}`,
+			"",
+		},
+		{
+			"unknown edge target",
+			`{"nodes":[{"id":"in","type":"inputNode"},{"id":"out","type":"outputNode"}],"edges":[{"id":"e1","sourceId":"in","targetId":"out"},{"id":"e2","sourceId":"in","targetId":"ghost"}]}`,
+			`edge "e2": target "ghost" does not exist`,
+		},
+		{"malformed JSON", `{"nodes": [`, "invalid JDM JSON"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ferr := testRunFunction(t, NewValidateJDMFunction(), types.StringValue(tc.json))
+			if ferr != nil {
+				t.Fatalf("function error: %s", ferr)
+			}
+			list, ok := got.(types.List)
+			if !ok {
+				t.Fatalf("result is %T, want a list", got)
+			}
+			elems := list.Elements()
+			if tc.want == "" {
+				if len(elems) != 0 {
+					t.Errorf("got %v, want no messages", elems)
+				}
+				return
+			}
+			if len(elems) != 1 || !strings.Contains(elems[0].(types.String).ValueString(), tc.want) {
+				t.Errorf("got %v, want one message containing %q", elems, tc.want)
+			}
+		})
+	}
+}
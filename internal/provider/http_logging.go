@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// -----------------------------------------------------------------------------
+// API call logging
+//
+// Every request goes through loggingTransport. At DEBUG it logs method, URL,
+// status, duration and the request ID; at TRACE it adds headers and bodies.
+// Authorization and secret-looking JSON members are masked, and so is any
+// value registered in mask (the provider token).
+// -----------------------------------------------------------------------------
+
+const maskedValue = "***"
+
+// response headers carrying the server-side request ID, in order of preference
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"}
+
+// headers never logged in clear
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// JSON members masked in logged bodies
+var reSecretKey = regexp.MustCompile(`(?i)(token|secret|password|passwd|api[-_]?key|credential|authorization)`)
+
+type loggingTransport struct {
+	next http.RoundTripper
+	mask []string
+}
+
+func newLoggingTransport(next http.RoundTripper, mask ...string) *loggingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &loggingTransport{next: next}
+	for _, m := range mask {
+		if m != "" {
+			t.mask = append(t.mask, m)
+		}
+	}
+	return t
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	fields := map[string]interface{}{
+		"http_method": req.Method,
+		"http_url":    req.URL.Redacted(),
+	}
+
+	trace := map[string]interface{}{"http_request_headers": t.headers(req.Header)}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			raw, _ := io.ReadAll(body)
+			body.Close()
+			trace["http_request_body"] = t.body(raw)
+		}
+	}
+	tflog.Trace(ctx, "GoRules API request", mergeFields(fields, trace))
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = t.redact(err.Error())
+		tflog.Debug(ctx, "GoRules API call failed", fields)
+		return nil, err
+	}
+
+	fields["http_status"] = res.StatusCode
+	for _, h := range requestIDHeaders {
+		if id := res.Header.Get(h); id != "" {
+			fields["request_id"] = id
+			break
+		}
+	}
+	tflog.Debug(ctx, "GoRules API call", fields)
+
+	// buffer the body so it can be logged and still read by the caller
+	raw, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(raw))
+	tflog.Trace(ctx, "GoRules API response", mergeFields(fields, map[string]interface{}{
+		"http_response_headers": t.headers(res.Header),
+		"http_response_body":    t.body(raw),
+	}))
+	return res, nil
+}
+
+func (t *loggingTransport) headers(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			out[k] = maskedValue
+			continue
+		}
+		out[k] = t.redact(strings.Join(v, ", "))
+	}
+	return out
+}
+
+// body renders a request/response body for logs, masking secrets
+func (t *loggingTransport) body(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err == nil {
+		if masked, err := json.Marshal(maskSecrets(v)); err == nil {
+			raw = masked
+		}
+	}
+	return t.redact(string(raw))
+}
+
+func (t *loggingTransport) redact(s string) string {
+	for _, m := range t.mask {
+		s = strings.ReplaceAll(s, m, maskedValue)
+	}
+	return s
+}
+
+// maskSecrets replaces the values of secret-looking members, recursively
+func maskSecrets(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, val := range x {
+			if reSecretKey.MatchString(k) {
+				x[k] = maskedValue
+			} else {
+				x[k] = maskSecrets(val)
+			}
+		}
+	case []interface{}:
+		for i := range x {
+			x[i] = maskSecrets(x[i])
+		}
+	}
+	return v
+}
+
+func mergeFields(a, b map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	var out bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &out)
+	client := &http.Client{Transport: newLoggingTransport(nil, srv.Token)}
+
+	body := `{"name":"Pricing","key":"pricing","clientSecret":"s3cr3t","note":"token ` + srv.Token + `"}`
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/api/projects", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+srv.Token)
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	logs := out.String()
+	for _, secret := range []string{srv.Token, "s3cr3t"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain secret %q:\n%s", secret, logs)
+		}
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var call map[string]interface{}
+	for _, e := range entries {
+		if e["@message"] == "GoRules API call" {
+			call = e
+		}
+	}
+	if call == nil {
+		t.Fatalf("no DEBUG entry for the call: %v", entries)
+	}
+	if call["@level"] != "debug" || call["http_method"] != "POST" || call["http_status"] != float64(201) {
+		t.Errorf("unexpected DEBUG entry: %v", call)
+	}
+	if call["request_id"] == nil {
+		t.Errorf("missing request_id: %v", call)
+	}
+	if _, ok := call["duration_ms"]; !ok {
+		t.Errorf("missing duration_ms: %v", call)
+	}
+	if _, ok := call["http_request_body"]; ok {
+		t.Errorf("bodies must only be logged at TRACE: %v", call)
+	}
+}
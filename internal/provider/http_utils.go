@@ -1,47 +1,127 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 // -----------------------------------------------------------------------------
-// HTTP client without redirects (maintains Authorization header)
+// HTTP client following same-origin redirects
+//
+// Load balancers commonly redirect http→https or add a trailing slash. Those
+// redirects are followed; authTransport sets the Authorization header on every
+// hop, so redirects to another host are never followed (the token would leak
+// there) and surface as a 3xx API error instead. 307/308 keep the method and
+// body; a 301/302/303 that would turn a write into a GET is not followed
+// either, so an update can never silently become a read.
 // -----------------------------------------------------------------------------
-func clientNoRedirect(base *http.Client) *http.Client {
-	if base == nil {
-		return &http.Client{
-			CheckRedirect: func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse },
-		}
+
+const maxRedirects = 10
+
+func apiClient(base *http.Client) *http.Client {
+	c := &http.Client{}
+	if base != nil {
+		c2 := *base
+		c = &c2
+	}
+	c.CheckRedirect = checkRedirect
+	return c
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	prev := via[len(via)-1]
+	if !sameOrigin(via[0].URL, req.URL) || req.Method != prev.Method {
+		return http.ErrUseLastResponse
+	}
+	return nil
+}
+
+// sameOrigin reports whether a redirect from u to next stays on the same
+// origin; an upgrade from http to https on the same host is allowed
+func sameOrigin(u, next *url.URL) bool {
+	if !strings.EqualFold(u.Hostname(), next.Hostname()) {
+		return false
+	}
+	switch {
+	case u.Scheme == next.Scheme:
+		return portOf(u) == portOf(next)
+	case u.Scheme == "http" && next.Scheme == "https":
+		return portOf(next) == "443"
 	}
-	c := *base
-	c.CheckRedirect = func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }
-	return &c
+	return false
+}
+
+func portOf(u *url.URL) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+	if u.Scheme == "https" {
+		return "443"
+	}
+	return "80"
 }
 
 // -----------------------------------------------------------------------------
 // Various utilities
 // -----------------------------------------------------------------------------
 
-// slugify converts a string to kebab-case (useful for resource names)
+// slugify converts a string to kebab-case ASCII (accents are transliterated)
 var reNotAllowed = regexp.MustCompile(`[^a-z0-9-]`)
 var reDashCollapse = regexp.MustCompile(`-+`)
 
+// Letters that don't decompose into ASCII + combining marks
+var asciiFold = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "ae", "œ", "oe", "Œ", "oe", "ø", "o", "Ø", "o",
+	"đ", "d", "Đ", "d", "ł", "l", "Ł", "l", "þ", "th", "Þ", "th", "ð", "d", "Ð", "d",
+)
+
 func slugify(s string) string {
+	s = asciiFold.Replace(s)
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(t, s); err == nil {
+		s = folded
+	}
 	s = strings.ToLower(s)
 	s = reNotAllowed.ReplaceAllString(s, "-")
 	s = reDashCollapse.ReplaceAllString(s, "-")
 	return strings.Trim(s, "-")
 }
 
+// Project keys accepted by the BRMS
+var reProjectKey = regexp.MustCompile(`^[a-z0-9]{2,}(-[a-z0-9]+)*$`)
+
+// projectKeyFromName derives a valid project key from a display name. The
+// first segment must have at least 2 characters, so short leading words are
+// joined with the next one ("A Team" -> "ateam").
+func projectKeyFromName(name string) (string, error) {
+	parts := strings.Split(slugify(name), "-")
+	for len(parts) > 1 && len(parts[0]) < 2 {
+		parts = append([]string{parts[0] + parts[1]}, parts[2:]...)
+	}
+	key := strings.Join(parts, "-")
+	if !reProjectKey.MatchString(key) {
+		return "", fmt.Errorf("cannot derive a project key from %q: need at least 2 ASCII letters or digits", name)
+	}
+	return key, nil
+}
+
 // -----------------------------------------------------------------------------
 // Shared Terraform helpers (for all resources)
 // -----------------------------------------------------------------------------
@@ -68,6 +148,54 @@ func ToTFStringListSorted(xs []string) []types.String {
 	return ToTFStringList(cp)
 }
 
+// ToTFStringListKeepOrder converts []string -> []types.String, keeping the
+// order of prior (plan or state) when both hold the same elements. The API
+// may return lists in any order; this avoids diffs and "inconsistent result
+// after apply" errors for lists that only differ in ordering.
+func ToTFStringListKeepOrder(xs []string, prior []types.String) []types.String {
+	if len(prior) != len(xs) {
+		return ToTFStringList(xs)
+	}
+	counts := map[string]int{}
+	for _, v := range xs {
+		counts[v]++
+	}
+	for _, p := range prior {
+		if p.IsNull() || p.IsUnknown() || counts[p.ValueString()] == 0 {
+			return ToTFStringList(xs)
+		}
+		counts[p.ValueString()]--
+	}
+	out := make([]types.String, len(prior))
+	copy(out, prior)
+	return out
+}
+
+// jsonKind reports the JSON type of raw ("object", "array", "string",
+// "number", "bool", "null"), "missing" for empty input and "invalid" for
+// anything else. Only the first byte is inspected; decoding still validates.
+func jsonKind(raw []byte) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "missing"
+	}
+	switch c := raw[0]; {
+	case c == '{':
+		return "object"
+	case c == '[':
+		return "array"
+	case c == '"':
+		return "string"
+	case c == '-' || (c >= '0' && c <= '9'):
+		return "number"
+	case c == 't' || c == 'f':
+		return "bool"
+	case c == 'n':
+		return "null"
+	}
+	return "invalid"
+}
+
 // -----------------------------------------------------------------------------
 // Helpers for resolving Groups (ID <-> Name)
 // -----------------------------------------------------------------------------
@@ -98,21 +226,20 @@ func ResolveGroupIDsByName(ctx context.Context, cfg *Config, projectID string, n
 	}
 	url := fmt.Sprintf("%s/api/projects/%s/groups?perPage=500", cfg.BaseURL, projectID)
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	req.Header.Set("Authorization", "Bearer "+cfg.Token)
 
-	res, err := clientNoRedirect(cfg.HTTP).Do(req)
+	res, err := apiClient(cfg.HTTP).Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error listing groups: %w", err)
+		return nil, fmt.Errorf("listing groups: %w", err)
 	}
 	defer res.Body.Close()
 	if res.StatusCode >= 300 {
 		raw, _ := io.ReadAll(res.Body)
-		return nil, fmt.Errorf("status=%d body=%s", res.StatusCode, string(raw))
+		return nil, fmt.Errorf("listing groups: %w", checkResponse(res, raw))
 	}
 
 	var gl groupListResponse
 	if err := json.NewDecoder(res.Body).Decode(&gl); err != nil {
-		return nil, fmt.Errorf("error parsing groups: %w", err)
+		return nil, fmt.Errorf("parsing groups: %w", err)
 	}
 
 	byName := map[string]string{}
@@ -136,21 +263,20 @@ func ResolveGroupNamesByID(ctx context.Context, cfg *Config, projectID string, i
 	}
 	url := fmt.Sprintf("%s/api/projects/%s/groups?perPage=500", cfg.BaseURL, projectID)
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	req.Header.Set("Authorization", "Bearer "+cfg.Token)
 
-	res, err := clientNoRedirect(cfg.HTTP).Do(req)
+	res, err := apiClient(cfg.HTTP).Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error listing groups: %w", err)
+		return nil, fmt.Errorf("listing groups: %w", err)
 	}
 	defer res.Body.Close()
 	if res.StatusCode >= 300 {
 		raw, _ := io.ReadAll(res.Body)
-		return nil, fmt.Errorf("status=%d body=%s", res.StatusCode, string(raw))
+		return nil, fmt.Errorf("listing groups: %w", checkResponse(res, raw))
 	}
 
 	var gl groupListResponse
 	if err := json.NewDecoder(res.Body).Decode(&gl); err != nil {
-		return nil, fmt.Errorf("error parsing groups: %w", err)
+		return nil, fmt.Errorf("parsing groups: %w", err)
 	}
 
 	byID := map[string]string{}
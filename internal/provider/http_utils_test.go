@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+func TestSameOrigin(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     bool
+	}{
+		{"https://brms.example.com/api/projects", "https://brms.example.com/api/projects/", true},
+		{"https://brms.example.com/api", "https://BRMS.example.com:443/v2/api", true},
+		{"http://brms.example.com/api", "https://brms.example.com/api", true},
+		{"http://brms.example.com:8080/api", "https://brms.example.com:8443/api", false},
+		{"https://brms.example.com/api", "http://brms.example.com/api", false},
+		{"https://brms.example.com/api", "https://brms.example.com:8443/api", false},
+		{"https://brms.example.com/api", "https://sso.example.com/login", false},
+	}
+	for _, tc := range cases {
+		from, _ := url.Parse(tc.from)
+		to, _ := url.Parse(tc.to)
+		if got := sameOrigin(from, to); got != tc.want {
+			t.Errorf("sameOrigin(%s, %s) = %t, want %t", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestAPIClientRedirects(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	p := srv.AddProject(mockserver.Project{Name: "Pricing", Key: "pricing"})
+	client := apiClient(&http.Client{Transport: &authTransport{next: http.DefaultTransport, source: staticToken(srv.Token)}})
+
+	// 308 keeps the method, body and Authorization header
+	srv.Inject(mockserver.Fault{Method: http.MethodPut, Status: http.StatusPermanentRedirect, Location: "/api/projects/" + p.ID, Times: 1})
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/api/projects/"+p.ID+"/", strings.NewReader(`{"name":"Renamed"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if got, _ := srv.Project(p.ID); res.StatusCode != http.StatusOK || got.Name != "Renamed" {
+		t.Errorf("308: status %d, project %+v", res.StatusCode, got)
+	}
+
+	// a 301 would turn the PUT into a GET: not followed
+	srv.Inject(mockserver.Fault{Method: http.MethodPut, Status: http.StatusMovedPermanently, Location: "/api/projects/" + p.ID, Times: 1})
+	req, _ = http.NewRequest(http.MethodPut, srv.URL+"/api/projects/"+p.ID, strings.NewReader(`{"name":"Again"}`))
+	res, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("301 on PUT: got status %d", res.StatusCode)
+	}
+
+	// another host never sees the token
+	var hits atomic.Int32
+	other := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hits.Add(1) }))
+	defer other.Close()
+	srv.Inject(mockserver.Fault{Status: http.StatusFound, Location: other.URL + "/api/projects", Times: 1})
+	res, err = client.Get(srv.URL + "/api/projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusFound || hits.Load() != 0 {
+		t.Errorf("cross-host: status %d, %d requests to the other host", res.StatusCode, hits.Load())
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"Pricing Rules", "pricing-rules"},
+		{"Tarificación Europa", "tarificacion-europa"},
+		{"Crème Brûlée", "creme-brulee"},
+		{"Straße Ærø Œuvre", "strasse-aero-oeuvre"},
+		{"Łódź Þing", "lodz-thing"},
+		{"a -- b__c  d", "a-b-c-d"},
+		{"--Edge--", "edge"},
+		{"!!!", ""},
+	}
+	for _, tc := range cases {
+		if got := slugify(tc.in); got != tc.want {
+			t.Errorf("slugify(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestProjectKeyFromName(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"Pricing Rules", "pricing-rules"},
+		{"Tarificación Europa", "tarificacion-europa"},
+		{"A Team", "ateam"},
+		{"A B Testing", "ab-testing"},
+		{"X", ""},
+		{"Pricing v2", "pricing-v2"},
+		{"Rules - 2024 / Q1", "rules-2024-q1"},
+		{"***", ""},
+		{"日本", ""},
+	}
+	for _, tc := range cases {
+		got, err := projectKeyFromName(tc.in)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("projectKeyFromName(%q) = %q, want an error", tc.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("projectKeyFromName(%q) = %q, %v, want %q", tc.in, got, err, tc.want)
+		}
+	}
+}
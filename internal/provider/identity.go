@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// -----------------------------------------------------------------------------
+// Resource identity
+//
+// Identities only hold server-assigned IDs: names and keys can change in
+// place, and Terraform rejects an identity that changes. Import blocks can use
+// either `identity = {...}` or the classic import ID string.
+// -----------------------------------------------------------------------------
+
+// projectIdentityModel is the identity of gorules_project
+type projectIdentityModel struct {
+	ID types.String `tfsdk:"id"`
+}
+
+// projectChildIdentityModel is the identity of resources that live under a
+// project (environments, groups)
+type projectChildIdentityModel struct {
+	ProjectID types.String `tfsdk:"project_id"`
+	ID        types.String `tfsdk:"id"`
+}
+
+func projectIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Project ID.",
+			},
+		},
+	}
+}
+
+func projectChildIdentitySchema(kind string) identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"project_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "ID of the project the " + kind + " belongs to.",
+			},
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       strings.ToUpper(kind[:1]) + kind[1:] + " ID.",
+			},
+		},
+	}
+}
+
+// importStateProjectChild imports "<project_id>/<id>", or an identity with
+// project_id and id, for resources that live under a project
+func importStateProjectChild(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" && req.Identity != nil {
+		var identity projectChildIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), identity.ProjectID)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+		return
+	}
+
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("Expected <project_id>/<id>, got %q", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+}
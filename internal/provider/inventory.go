@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Inventory (used by cmd/gorules-export)
+//
+// Walks projects, groups and environments with the same API code as the
+// resources, for tools that run outside Terraform.
+// -----------------------------------------------------------------------------
+
+type Inventory struct {
+	Projects []InventoryProject
+}
+
+type InventoryProject struct {
+	ID        string
+	Name      string
+	Key       string
+	Protected *bool
+	Groups    []InventoryGroup
+	// Environments reference groups by ID (see InventoryGroup.ID)
+	Environments []InventoryEnvironment
+}
+
+type InventoryGroup struct {
+	ID          string
+	Name        string
+	Description string
+	Permissions []string // sorted
+}
+
+type InventoryEnvironment struct {
+	ID               string
+	Name             string
+	Key              string
+	Type             string
+	ApprovalMode     *string
+	ApprovalGroupIDs []string
+}
+
+// NewClientConfig returns an API client configuration authenticated with a
+// static token. userAgent identifies the calling tool (e.g. "gorules-export/1.2.0").
+func NewClientConfig(baseURL, token, userAgent string, timeout time.Duration) *Config {
+	return &Config{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		HTTP: &http.Client{
+			Timeout: timeout,
+			Transport: &authTransport{
+				next:   &headerTransport{next: http.DefaultTransport, userAgent: userAgent},
+				source: staticToken(token),
+			},
+		},
+	}
+}
+
+// ReadInventory lists every project visible to the token, or only those with
+// the given keys, with their groups and environments
+func ReadInventory(ctx context.Context, cfg *Config, projectKeys ...string) (*Inventory, error) {
+	projects, err := listProjects(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+	if len(projectKeys) > 0 {
+		var selected []projectFlat
+		for _, key := range projectKeys {
+			found := projectsWithKey(projects, key)
+			if len(found) == 0 {
+				return nil, fmt.Errorf("no project with key %q is visible to the token", key)
+			}
+			selected = append(selected, found...)
+		}
+		projects = selected
+	}
+
+	envs := &environmentResource{cfg: cfg}
+	groups := &groupResource{cfg: cfg}
+	inv := &Inventory{}
+	for _, p := range projects {
+		ip := InventoryProject{ID: p.ID, Name: p.Name, Key: p.Key, Protected: p.Protected}
+
+		gs, _, _, err := groups.listAllGroups(ctx, p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing groups of project %q: %w", p.Key, err)
+		}
+		for _, g := range gs {
+			ig := InventoryGroup{ID: g.ID, Name: g.Name, Permissions: groups.normalizePerms(g.Permissions)}
+			if g.Description != nil {
+				ig.Description = *g.Description
+			}
+			ip.Groups = append(ip.Groups, ig)
+		}
+
+		es, _, _, err := envs.listEnvironments(ctx, p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing environments of project %q: %w", p.Key, err)
+		}
+		for _, e := range es {
+			ip.Environments = append(ip.Environments, InventoryEnvironment{
+				ID:               e.ID,
+				Name:             e.Name,
+				Key:              e.Key,
+				Type:             e.Type,
+				ApprovalMode:     e.ApprovalMode,
+				ApprovalGroupIDs: e.ApprovalGroups,
+			})
+		}
+		inv.Projects = append(inv.Projects, ip)
+	}
+	return inv, nil
+}
+
+// ResourceLabel turns a name or key into a Terraform resource name
+// ("Tarificación Europa" -> "tarificacion_europa")
+func ResourceLabel(s string) string {
+	label := strings.ReplaceAll(slugify(s), "-", "_")
+	if label == "" {
+		return "unnamed"
+	}
+	if label[0] >= '0' && label[0] <= '9' {
+		return "_" + label
+	}
+	return label
+}
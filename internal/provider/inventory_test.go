@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+func TestReadInventory(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	pricing := srv.AddProject(mockserver.Project{Name: "Pricing", Key: "pricing"})
+	srv.AddProject(mockserver.Project{Name: "Claims", Key: "claims"})
+	g := srv.AddGroup(mockserver.Group{ProjectID: pricing.ID, Name: "approvers", Permissions: []string{"deploy", "approve"}})
+	srv.AddEnvironment(mockserver.Environment{ProjectID: pricing.ID, Name: "production", Key: "production", Type: "brms", ApprovalGroups: []string{g.ID}})
+	cfg := NewClientConfig(srv.URL, srv.Token, "gorules-export/test", 5*time.Second)
+
+	inv, err := ReadInventory(context.Background(), cfg, "pricing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inv.Projects) != 1 {
+		t.Fatalf("got %d projects", len(inv.Projects))
+	}
+	p := inv.Projects[0]
+	if len(p.Groups) != 1 || strings.Join(p.Groups[0].Permissions, ",") != "approve,deploy" {
+		t.Errorf("groups: %+v", p.Groups)
+	}
+	if len(p.Environments) != 1 || strings.Join(p.Environments[0].ApprovalGroupIDs, ",") != g.ID {
+		t.Errorf("environments: %+v", p.Environments)
+	}
+	if got := srv.Requests()[0].Header.Get("User-Agent"); got != "gorules-export/test" {
+		t.Errorf("User-Agent = %q", got)
+	}
+
+	if _, err := ReadInventory(context.Background(), cfg, "nope"); err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("unknown key: got %v", err)
+	}
+}
+
+func TestResourceLabel(t *testing.T) {
+	for in, want := range map[string]string{
+		"pricing-rules":       "pricing_rules",
+		"Tarificación Europa": "tarificacion_europa",
+		"2024 rules":          "_2024_rules",
+		"¿?":                  "unnamed",
+	} {
+		if got := ResourceLabel(in); got != want {
+			t.Errorf("ResourceLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	lschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// -----------------------------------------------------------------------------
+// List resources (`terraform query`, Terraform 1.14+)
+//
+// Each managed resource type has a list resource of the same name, built on
+// the listings the resources already use. Results carry the resource identity
+// and, with include_resource, the attributes Read would store.
+// -----------------------------------------------------------------------------
+
+type projectListResource struct{ cfg *Config }
+
+type projectListModel struct {
+	Key       types.String `tfsdk:"key"`
+	NameRegex types.String `tfsdk:"name_regex"`
+}
+
+// environments and groups share their filters
+type projectChildListResource struct {
+	cfg  *Config
+	kind string // "environment" or "group"
+}
+
+type projectChildListModel struct {
+	ProjectKey types.String `tfsdk:"project_key"`
+	NameRegex  types.String `tfsdk:"name_regex"`
+}
+
+func NewProjectListResource() list.ListResource { return &projectListResource{} }
+
+func NewEnvironmentListResource() list.ListResource {
+	return &projectChildListResource{kind: "environment"}
+}
+
+func NewGroupListResource() list.ListResource { return &projectChildListResource{kind: "group"} }
+
+func (l *projectListResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "gorules_project"
+}
+
+func (l *projectChildListResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "gorules_" + l.kind
+}
+
+func (l *projectListResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		l.cfg = req.ProviderData.(*Config)
+	}
+}
+
+func (l *projectChildListResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		l.cfg = req.ProviderData.(*Config)
+	}
+}
+
+var nameRegexAttribute = lschema.StringAttribute{
+	Optional:            true,
+	MarkdownDescription: "Only list objects whose name matches this regular expression (RE2 syntax, unanchored).",
+}
+
+func (l *projectListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = lschema.Schema{
+		MarkdownDescription: "Lists the projects visible to the provider token.",
+		Attributes: map[string]lschema.Attribute{
+			"key": lschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the project with this key.",
+			},
+			"name_regex": nameRegexAttribute,
+		},
+	}
+}
+
+func (l *projectChildListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = lschema.Schema{
+		MarkdownDescription: fmt.Sprintf("Lists the %ss of one project, or of every project visible to the provider token.", l.kind),
+		Attributes: map[string]lschema.Attribute{
+			"project_key": lschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf("Only list the %ss of the project with this key.", l.kind),
+			},
+			"name_regex": nameRegexAttribute,
+		},
+	}
+}
+
+func (l *projectListResource) ValidateListResourceConfig(ctx context.Context, req list.ValidateConfigRequest, resp *list.ValidateConfigResponse) {
+	var cfg projectListModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	_, diags := compileNameRegex(cfg.NameRegex)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (l *projectChildListResource) ValidateListResourceConfig(ctx context.Context, req list.ValidateConfigRequest, resp *list.ValidateConfigResponse) {
+	var cfg projectChildListModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	_, diags := compileNameRegex(cfg.NameRegex)
+	resp.Diagnostics.Append(diags...)
+}
+
+// compileNameRegex returns nil when name_regex is not set
+func compileNameRegex(v types.String) (*regexp.Regexp, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if v.IsNull() || v.IsUnknown() {
+		return nil, diags
+	}
+	re, err := regexp.Compile(v.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("name_regex"), "Invalid name_regex", err.Error())
+	}
+	return re, diags
+}
+
+// -----------------------------------------------------------------------------
+// List
+// -----------------------------------------------------------------------------
+
+func (l *projectListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var cfg projectListModel
+	diags := req.Config.Get(ctx, &cfg)
+	nameRe, d := compileNameRegex(cfg.NameRegex)
+	diags.Append(d...)
+	if l.cfg == nil {
+		diags.AddError("provider not configured", "Missing base_url/token")
+	}
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	projects, err := listProjects(ctx, l.cfg)
+	if err != nil {
+		addAPIError(&diags, "List Projects", err, nil)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		n := int64(0)
+		for _, p := range projects {
+			if !cfg.Key.IsNull() && p.Key != cfg.Key.ValueString() {
+				continue
+			}
+			if nameRe != nil && !nameRe.MatchString(p.Name) {
+				continue
+			}
+			result := req.NewListResult(ctx)
+			result.DisplayName = p.Name
+			result.Diagnostics.Append(result.Identity.Set(ctx, projectIdentityModel{ID: types.StringValue(p.ID)})...)
+			if req.IncludeResource {
+				result.Diagnostics.Append(setAttributes(ctx, result.Resource, projectAttributes(p))...)
+			}
+			if !push(result) {
+				return
+			}
+			if n++; req.Limit > 0 && n >= req.Limit {
+				return
+			}
+		}
+	}
+}
+
+func (l *projectChildListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var cfg projectChildListModel
+	diags := req.Config.Get(ctx, &cfg)
+	nameRe, d := compileNameRegex(cfg.NameRegex)
+	diags.Append(d...)
+	if l.cfg == nil {
+		diags.AddError("provider not configured", "Missing base_url/token")
+	}
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	projects, err := listProjects(ctx, l.cfg)
+	if err != nil {
+		addAPIError(&diags, "List Projects", err, nil)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	if !cfg.ProjectKey.IsNull() {
+		projects = projectsWithKey(projects, cfg.ProjectKey.ValueString())
+		if len(projects) == 0 {
+			diags.AddAttributeError(path.Root("project_key"), "Project not found",
+				fmt.Sprintf("No project with key %q is visible to the provider token.", cfg.ProjectKey.ValueString()))
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	// listings are fetched per project while results are consumed
+	stream.Results = func(push func(list.ListResult) bool) {
+		n := int64(0)
+		for _, p := range projects {
+			items, diags := l.listProject(ctx, p.ID, req.IncludeResource)
+			if diags.HasError() {
+				push(list.ListResult{Diagnostics: diags})
+				return
+			}
+			for _, it := range items {
+				if nameRe != nil && !nameRe.MatchString(it.name) {
+					continue
+				}
+				result := req.NewListResult(ctx)
+				result.DisplayName = p.Name + " / " + it.name
+				result.Diagnostics.Append(result.Identity.Set(ctx, projectChildIdentityModel{
+					ProjectID: types.StringValue(p.ID),
+					ID:        types.StringValue(it.id),
+				})...)
+				if req.IncludeResource {
+					result.Diagnostics.Append(setAttributes(ctx, result.Resource, it.attributes)...)
+				}
+				if !push(result) {
+					return
+				}
+				if n++; req.Limit > 0 && n >= req.Limit {
+					return
+				}
+			}
+		}
+	}
+}
+
+type listedChild struct {
+	id, name   string
+	attributes map[string]any // resource attributes, only with include_resource
+}
+
+func (l *projectChildListResource) listProject(ctx context.Context, projectID string, includeResource bool) ([]listedChild, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	// environments only need the groups to name their approvers
+	var groups []groupItem
+	if l.kind == "group" || includeResource {
+		var err error
+		groups, _, _, err = (&groupResource{cfg: l.cfg}).listAllGroups(ctx, projectID)
+		if err != nil {
+			addAPIError(&diags, "List Groups", err, nil)
+			return nil, diags
+		}
+	}
+
+	var out []listedChild
+	if l.kind == "group" {
+		for _, g := range groups {
+			out = append(out, listedChild{id: g.ID, name: g.Name, attributes: groupAttributes(projectID, g)})
+		}
+		return out, diags
+	}
+
+	envs, _, _, err := (&environmentResource{cfg: l.cfg}).listEnvironments(ctx, projectID)
+	if err != nil {
+		addAPIError(&diags, "List Environments", err, nil)
+		return nil, diags
+	}
+	groupNames := make(map[string]string, len(groups))
+	for _, g := range groups {
+		groupNames[g.ID] = g.Name
+	}
+	for _, e := range envs {
+		out = append(out, listedChild{id: e.ID, name: e.Name, attributes: environmentAttributes(projectID, e, groupNames)})
+	}
+	return out, diags
+}
+
+func projectsWithKey(projects []projectFlat, key string) []projectFlat {
+	for _, p := range projects {
+		if p.Key == key {
+			return []projectFlat{p}
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// API objects → resource attributes (as Read stores them)
+// -----------------------------------------------------------------------------
+
+func projectAttributes(p projectFlat) map[string]any {
+	return map[string]any{
+		"id":            p.ID,
+		"name":          p.Name,
+		"key":           p.Key,
+		"protected":     p.Protected,
+		"force_destroy": false,
+	}
+}
+
+func environmentAttributes(projectID string, e envItem, groupNames map[string]string) map[string]any {
+	names := make([]string, 0, len(e.ApprovalGroups))
+	for _, id := range e.ApprovalGroups {
+		if n, ok := groupNames[id]; ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return map[string]any{
+		"id":              e.ID,
+		"project_id":      projectID,
+		"name":            e.Name,
+		"key":             e.Key,
+		"type":            e.Type,
+		"approval_mode":   e.ApprovalMode,
+		"approval_groups": names,
+	}
+}
+
+func groupAttributes(projectID string, g groupItem) map[string]any {
+	description := ""
+	if g.Description != nil {
+		description = *g.Description
+	}
+	return map[string]any{
+		"id":          g.ID,
+		"project_id":  projectID,
+		"name":        g.Name,
+		"description": description,
+		// what Read stores on import, so generated config plans no diff
+		"permissions": (&groupResource{}).permissionsState(g.Permissions, nil),
+	}
+}
+
+// setAttributes sets top-level attributes; the others stay null
+func setAttributes(ctx context.Context, res *tfsdk.Resource, attrs map[string]any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		diags.Append(res.SetAttribute(ctx, path.Root(name), attrs[name])...)
+	}
+	return diags
+}
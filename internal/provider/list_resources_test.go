@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+// testList runs l.List with the given config attributes and returns the
+// display names, or the first error summary
+func testList(t *testing.T, cfg *Config, l list.ListResource, r resource.ResourceWithIdentity, config map[string]string, include bool) ([]string, []list.ListResult) {
+	t.Helper()
+	ctx := context.Background()
+
+	var cs list.ListResourceSchemaResponse
+	l.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &cs)
+	values := map[string]tftypes.Value{}
+	for name := range cs.Schema.Attributes {
+		var v any
+		if s, ok := config[name]; ok {
+			v = s
+		}
+		values[name] = tftypes.NewValue(tftypes.String, v)
+	}
+	var rs resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &rs)
+	var is resource.IdentitySchemaResponse
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &is)
+
+	l.(list.ListResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: cfg}, &resource.ConfigureResponse{})
+	stream := &list.ListResultsStream{}
+	l.List(ctx, list.ListRequest{
+		Config:                 tfsdk.Config{Schema: cs.Schema, Raw: tftypes.NewValue(cs.Schema.Type().TerraformType(ctx), values)},
+		IncludeResource:        include,
+		ResourceSchema:         rs.Schema,
+		ResourceIdentitySchema: is.IdentitySchema,
+	}, stream)
+
+	var names []string
+	var results []list.ListResult
+	for res := range stream.Results {
+		if res.Diagnostics.HasError() {
+			return []string{"error: " + res.Diagnostics.Errors()[0].Summary()}, nil
+		}
+		names = append(names, res.DisplayName)
+		results = append(results, res)
+	}
+	return names, results
+}
+
+func TestListResources(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	cfg := testServerConfig(srv.URL, srv.Token)
+
+	pricing := srv.AddProject(mockserver.Project{Name: "Pricing", Key: "pricing"})
+	claims := srv.AddProject(mockserver.Project{Name: "Claims", Key: "claims"})
+	approvers := srv.AddGroup(mockserver.Group{ProjectID: pricing.ID, Name: "approvers", Permissions: []string{"deploy"}})
+	srv.AddGroup(mockserver.Group{ProjectID: claims.ID, Name: "editors", Permissions: []string{"publish", "deploy"}})
+	srv.AddEnvironment(mockserver.Environment{ProjectID: pricing.ID, Name: "production", Key: "production", Type: "brms", ApprovalGroups: []string{approvers.ID}})
+	srv.AddEnvironment(mockserver.Environment{ProjectID: pricing.ID, Name: "staging", Key: "staging", Type: "brms"})
+	srv.AddEnvironment(mockserver.Environment{ProjectID: claims.ID, Name: "production", Key: "production", Type: "brms"})
+
+	projects, envs, groups := NewProjectListResource, NewEnvironmentListResource, NewGroupListResource
+	cases := []struct {
+		name   string
+		list   func() list.ListResource
+		res    func() resource.Resource
+		config map[string]string
+		want   string
+	}{
+		{"all projects", projects, NewProjectResource, nil, "Pricing,Claims"},
+		{"project by key", projects, NewProjectResource, map[string]string{"key": "claims"}, "Claims"},
+		{"projects by name", projects, NewProjectResource, map[string]string{"name_regex": "^P"}, "Pricing"},
+		{"all environments", envs, NewEnvironmentResource, nil, "Pricing / production,Pricing / staging,Claims / production"},
+		{"environments of a project", envs, NewEnvironmentResource, map[string]string{"project_key": "claims"}, "Claims / production"},
+		{"environments by name", envs, NewEnvironmentResource, map[string]string{"name_regex": "prod"}, "Pricing / production,Claims / production"},
+		{"all groups", groups, NewGroupResource, nil, "Pricing / approvers,Claims / editors"},
+		{"unknown project", groups, NewGroupResource, map[string]string{"project_key": "nope"}, "error: Project not found"},
+		{"invalid regex", groups, NewGroupResource, map[string]string{"name_regex": "("}, "error: Invalid name_regex"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			names, _ := testList(t, cfg, tc.list(), tc.res().(resource.ResourceWithIdentity), tc.config, false)
+			if got := strings.Join(names, ","); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	// include_resource fills the attributes Read would store
+	_, results := testList(t, cfg, envs(), NewEnvironmentResource().(resource.ResourceWithIdentity), map[string]string{"project_key": "pricing", "name_regex": "production"}, true)
+	if len(results) != 1 {
+		t.Fatalf("got %d results", len(results))
+	}
+	var state environmentModel
+	var identity projectChildIdentityModel
+	ctx := context.Background()
+	if diags := results[0].Resource.Get(ctx, &state); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := results[0].Identity.Get(ctx, &identity); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if state.ProjectID.ValueString() != pricing.ID || state.Key.ValueString() != "production" ||
+		len(state.ApprovalGroups) != 1 || state.ApprovalGroups[0].ValueString() != "approvers" {
+		t.Errorf("resource: %+v", state)
+	}
+	if identity.ProjectID.ValueString() != pricing.ID || identity.ID != state.ID {
+		t.Errorf("identity: %+v", identity)
+	}
+
+	// permissions come in the order Read stores them on import
+	_, results = testList(t, cfg, groups(), NewGroupResource().(resource.ResourceWithIdentity), map[string]string{"project_key": "claims"}, true)
+	if len(results) != 1 {
+		t.Fatalf("got %d results", len(results))
+	}
+	var group groupModel
+	if diags := results[0].Resource.Get(ctx, &group); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(group.Permissions) != 2 || group.Permissions[0].ValueString() != "deploy" || group.Permissions[1].ValueString() != "publish" {
+		t.Errorf("permissions = %v, want [deploy publish]", group.Permissions)
+	}
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// -----------------------------------------------------------------------------
+// State moves (`moved { from = x.a  to = gorules_y.b }`, Terraform 1.8+)
+//
+// A resource accepts the state of the same kind of object from any GoRules
+// provider: ours under another type name (gorules_group_v2 → gorules_group and
+// back), or a fork/community provider whose source address ends in a name
+// containing "gorules" (e.g. example/gorules-brms). The source state is read
+// as plain JSON, so list and set attributes both work and unknown attributes
+// are ignored; only the IDs are required, Read fills in the rest.
+// -----------------------------------------------------------------------------
+
+// movableTypeName matches <provider>_<kind>[_v<n>] type names
+var movableTypeName = regexp.MustCompile(`^[a-z0-9]+(?:_[a-z0-9]+)*?_(project|environment|group)(?:_v[0-9]+)?$`)
+
+// movedFrom reports whether a moved block's source is a kind resource of a
+// GoRules provider
+func movedFrom(req resource.MoveStateRequest, kind string) bool {
+	addr := req.SourceProviderAddress
+	if !strings.Contains(addr[strings.LastIndex(addr, "/")+1:], "gorules") {
+		return false
+	}
+	m := movableTypeName.FindStringSubmatch(req.SourceTypeName)
+	return m != nil && m[1] == kind
+}
+
+// movedState is the union of the attributes copied from a source state;
+// attributes missing from the source stay null
+type movedState struct {
+	ID             *string  `json:"id"`
+	ProjectID      *string  `json:"project_id"`
+	Name           *string  `json:"name"`
+	Key            *string  `json:"key"`
+	Protected      *bool    `json:"protected"`
+	CopyContentRef *string  `json:"copy_content_ref"`
+	Type           *string  `json:"type"`
+	ApprovalMode   *string  `json:"approval_mode"`
+	ApprovalGroups []string `json:"approval_groups"`
+	Description    *string  `json:"description"`
+	Permissions    []string `json:"permissions"`
+}
+
+// decodeMovedState reads the source state and checks the required IDs
+func decodeMovedState(req resource.MoveStateRequest, resp *resource.MoveStateResponse, required ...string) (*movedState, bool) {
+	var s movedState
+	if req.SourceRawState == nil || json.Unmarshal(req.SourceRawState.JSON, &s) != nil {
+		resp.Diagnostics.AddError("Move State failed",
+			fmt.Sprintf("The state of %s could not be read as JSON.", req.SourceTypeName))
+		return nil, false
+	}
+	have := map[string]*string{"id": s.ID, "project_id": s.ProjectID}
+	var missing []string
+	for _, name := range required {
+		if v := have[name]; v == nil || *v == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		resp.Diagnostics.AddError("Move State failed",
+			fmt.Sprintf("The state of %s has no %s. Import the object instead of moving it.",
+				req.SourceTypeName, strings.Join(missing, ", ")))
+		return nil, false
+	}
+	return &s, true
+}
+
+func stringListOrNil(xs []string) []types.String {
+	if xs == nil {
+		return nil
+	}
+	out := make([]types.String, len(xs))
+	for i, x := range xs {
+		out[i] = types.StringValue(x)
+	}
+	return out
+}
+
+// -----------------------------------------------------------------------------
+// Resources
+// -----------------------------------------------------------------------------
+
+func (r *projectResource) MoveState(context.Context) []resource.StateMover {
+	return []resource.StateMover{{StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+		if !movedFrom(req, "project") {
+			return
+		}
+		s, ok := decodeMovedState(req, resp, "id")
+		if !ok {
+			return
+		}
+		resp.Diagnostics.Append(resp.TargetState.Set(ctx, projectModel{
+			ID:             types.StringPointerValue(s.ID),
+			Name:           types.StringPointerValue(s.Name),
+			Key:            types.StringPointerValue(s.Key),
+			Protected:      types.BoolPointerValue(s.Protected),
+			CopyContentRef: types.StringPointerValue(s.CopyContentRef),
+			ForceDestroy:   types.BoolValue(false),
+			Timeouts:       nullTimeouts(),
+		})...)
+		resp.Diagnostics.Append(resp.TargetIdentity.Set(ctx, projectIdentityModel{ID: types.StringPointerValue(s.ID)})...)
+	}}}
+}
+
+func (r *environmentResource) MoveState(context.Context) []resource.StateMover {
+	return []resource.StateMover{{StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+		if !movedFrom(req, "environment") {
+			return
+		}
+		s, ok := decodeMovedState(req, resp, "id", "project_id")
+		if !ok {
+			return
+		}
+		groups := stringListOrNil(s.ApprovalGroups)
+		if groups == nil {
+			groups = EmptyTFStringList()
+		}
+		resp.Diagnostics.Append(resp.TargetState.Set(ctx, environmentModel{
+			ID:             types.StringPointerValue(s.ID),
+			ProjectID:      types.StringPointerValue(s.ProjectID),
+			Name:           types.StringPointerValue(s.Name),
+			Key:            types.StringPointerValue(s.Key),
+			Type:           types.StringPointerValue(s.Type),
+			ApprovalMode:   types.StringPointerValue(s.ApprovalMode),
+			ApprovalGroups: groups,
+			Timeouts:       nullTimeouts(),
+		})...)
+		resp.Diagnostics.Append(resp.TargetIdentity.Set(ctx, projectChildIdentityModel{
+			ProjectID: types.StringPointerValue(s.ProjectID),
+			ID:        types.StringPointerValue(s.ID),
+		})...)
+	}}}
+}
+
+func (r *groupResource) MoveState(context.Context) []resource.StateMover {
+	return []resource.StateMover{{StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+		if !movedFrom(req, "group") {
+			return
+		}
+		s, ok := decodeMovedState(req, resp, "id", "project_id")
+		if !ok {
+			return
+		}
+		resp.Diagnostics.Append(resp.TargetState.Set(ctx, groupModel{
+			ID:          types.StringPointerValue(s.ID),
+			ProjectID:   types.StringPointerValue(s.ProjectID),
+			Name:        types.StringPointerValue(s.Name),
+			Description: types.StringPointerValue(s.Description),
+			Permissions: stringListOrNil(s.Permissions),
+			Timeouts:    nullTimeouts(),
+		})...)
+		resp.Diagnostics.Append(resp.TargetIdentity.Set(ctx, projectChildIdentityModel{
+			ProjectID: types.StringPointerValue(s.ProjectID),
+			ID:        types.StringPointerValue(s.ID),
+		})...)
+	}}}
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testMove runs the state movers of r on a source state and returns the
+// target state as JSON values, nil when no mover accepted the source, or the
+// first error detail
+func testMove(t *testing.T, r resource.ResourceWithMoveState, provider, typeName, state string) (any, string) {
+	t.Helper()
+	ctx := context.Background()
+	var rs resource.SchemaResponse
+	r.(resource.Resource).Schema(ctx, resource.SchemaRequest{}, &rs)
+	var is resource.IdentitySchemaResponse
+	r.(resource.ResourceWithIdentity).IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &is)
+
+	for _, mover := range r.MoveState(ctx) {
+		resp := resource.MoveStateResponse{
+			TargetState:    tfsdk.State{Schema: rs.Schema, Raw: tftypes.NewValue(rs.Schema.Type().TerraformType(ctx), nil)},
+			TargetIdentity: &tfsdk.ResourceIdentity{Schema: is.IdentitySchema, Raw: tftypes.NewValue(is.IdentitySchema.Type().TerraformType(ctx), nil)},
+		}
+		mover.StateMover(ctx, resource.MoveStateRequest{
+			SourceProviderAddress: provider,
+			SourceTypeName:        typeName,
+			SourceRawState:        &tfprotov6.RawState{JSON: []byte(state)},
+		}, &resp)
+		if resp.Diagnostics.HasError() {
+			return nil, resp.Diagnostics.Errors()[0].Detail()
+		}
+		if !resp.TargetState.Raw.IsNull() {
+			got, err := tfValueJSON(resp.TargetState.Raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.TargetIdentity.Raw.IsNull() {
+				t.Error("identity not set")
+			}
+			return got, ""
+		}
+	}
+	return nil, ""
+}
+
+func TestMoveState(t *testing.T) {
+	const ours = "registry.terraform.io/andredelgado-ruiz/gorules"
+
+	// group from a community provider, permissions stored as a set
+	got, errText := testMove(t, NewGroupResource().(resource.ResourceWithMoveState), "registry.terraform.io/example/gorules-brms", "gorulesbrms_group",
+		`{"id":"g1","project_id":"p1","name":"approvers","permissions":["deploy"],"members":["a@example.com"]}`)
+	want := map[string]any{"id": "g1", "project_id": "p1", "name": "approvers", "description": nil, "permissions": []any{"deploy"}, "timeouts": nil}
+	if errText != "" || !reflect.DeepEqual(got, want) {
+		t.Errorf("group: got %v %q", got, errText)
+	}
+
+	// from our own future type
+	got, _ = testMove(t, NewProjectResource().(resource.ResourceWithMoveState), ours, "gorules_project_v2", `{"id":"p1","name":"Pricing","key":"pricing"}`)
+	if m, _ := got.(map[string]any); m == nil || m["force_destroy"] != false || m["key"] != "pricing" {
+		t.Errorf("project: got %v", got)
+	}
+
+	// environment without project_id
+	if _, errText := testMove(t, NewEnvironmentResource().(resource.ResourceWithMoveState), ours, "gorules_environment_v2", `{"id":"e1"}`); errText == "" {
+		t.Error("environment without project_id: no error")
+	}
+
+	// other kinds and providers are left to Terraform's "Unable to Move" error
+	for _, src := range [][2]string{
+		{"registry.terraform.io/hashicorp/aws", "aws_iam_group"},
+		{ours, "gorules_environment"},
+		{ours, "gorules_group_membership"},
+	} {
+		if got, errText := testMove(t, NewGroupResource().(resource.ResourceWithMoveState), src[0], src[1], `{"id":"g1","project_id":"p1"}`); got != nil || errText != "" {
+			t.Errorf("%s %s: got %v %q", src[0], src[1], got, errText)
+		}
+	}
+}
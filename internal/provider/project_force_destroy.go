@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// -----------------------------------------------------------------------------
+// force_destroy: delete the children of a project before the project itself
+//
+// Order matters: deployments pin documents and environments, and environments
+// reference groups as approvers, so deployments go first, then documents,
+// environments and finally groups.
+// -----------------------------------------------------------------------------
+
+type projectChild struct {
+	collection string // path under /api/projects/{id}
+	list       func(ctx context.Context) ([]string, error)
+}
+
+// deleteChildren reports failures in diags and returns false if any
+func (r *projectResource) deleteChildren(ctx context.Context, projectID string, diags *diag.Diagnostics) bool {
+	envs := &environmentResource{cfg: r.cfg}
+	groups := &groupResource{cfg: r.cfg}
+	children := []projectChild{
+		{"deployments", func(ctx context.Context) ([]string, error) {
+			return r.listChildIDs(ctx, projectID, "deployments")
+		}},
+		{"documents", func(ctx context.Context) ([]string, error) {
+			return r.listChildIDs(ctx, projectID, "documents")
+		}},
+		{"environments", func(ctx context.Context) ([]string, error) {
+			items, _, _, err := envs.listEnvironments(ctx, projectID)
+			ids := make([]string, 0, len(items))
+			for _, it := range items {
+				ids = append(ids, it.ID)
+			}
+			return ids, err
+		}},
+		{"groups", func(ctx context.Context) ([]string, error) {
+			items, _, _, err := groups.listAllGroups(ctx, projectID)
+			ids := make([]string, 0, len(items))
+			for _, it := range items {
+				ids = append(ids, it.ID)
+			}
+			return ids, err
+		}},
+	}
+
+	for _, c := range children {
+		ids, err := c.list(ctx)
+		if isNotFound(err) {
+			// the server does not expose this collection
+			tflog.Debug(ctx, "force_destroy: no "+c.collection+" listing, skipped", map[string]interface{}{"project_id": projectID})
+			continue
+		}
+		if err != nil {
+			addAPIError(diags, "Force destroy: list "+c.collection, err, nil)
+			return false
+		}
+		tflog.Info(ctx, fmt.Sprintf("force_destroy: deleting %d %s", len(ids), c.collection), map[string]interface{}{"project_id": projectID})
+		for _, id := range ids {
+			if err := r.deleteChild(ctx, projectID, c.collection, id); err != nil && !isNotFound(err) {
+				addAPIError(diags, fmt.Sprintf("Force destroy: delete %s %s", c.collection, id), err, nil)
+				return false
+			}
+			tflog.Debug(ctx, "force_destroy: deleted "+c.collection, map[string]interface{}{"project_id": projectID, "id": id})
+		}
+	}
+	return true
+}
+
+// listChildIDs lists a collection answered as an array of objects with an id
+func (r *projectResource) listChildIDs(ctx context.Context, projectID, collection string) ([]string, error) {
+	url := fmt.Sprintf("%s/api/projects/%s/%s", r.cfg.BaseURL, projectID, collection)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := apiClient(r.cfg.HTTP).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	if err := checkResponse(res, raw); err != nil {
+		return nil, err
+	}
+	var items []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("%s response: expected array, got %s", collection, jsonKind(raw))
+	}
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		if it.ID != "" {
+			ids = append(ids, it.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *projectResource) deleteChild(ctx context.Context, projectID, collection, id string) error {
+	url := fmt.Sprintf("%s/api/projects/%s/%s/%s", r.cfg.BaseURL, projectID, collection, id)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := apiClient(r.cfg.HTTP).Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	return checkResponse(res, raw)
+}
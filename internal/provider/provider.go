@@ -2,10 +2,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	pframework "github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -16,13 +21,20 @@ type gorulesProvider struct{ version string }
 
 type gorulesProviderModel struct {
 	BaseURL types.String `tfsdk:"base_url"` // e.g. https://initial.gorules.io
-	Token   types.String `tfsdk:"token"`    // PAT (Bearer)
+	authModel
+	transportModel
+	ExtraHeaders              types.Map  `tfsdk:"extra_headers"`
+	SkipCredentialsValidation types.Bool `tfsdk:"skip_credentials_validation"`
 }
 
+// Config is shared with resources. HTTP authenticates every request (see
+// auth.go); resources never see the token. Server is nil when
+// skip_credentials_validation is set.
 type Config struct {
-	BaseURL string
-	Token   string
-	HTTP    *http.Client
+	BaseURL     string
+	HTTP        *http.Client
+	Server      *ServerInfo
+	Credentials path.Path // attribute of the configured credential source
 }
 
 func New(version string) pframework.Provider {
@@ -46,9 +58,107 @@ func (p *gorulesProvider) Schema(_ context.Context, _ pframework.SchemaRequest,
 				MarkdownDescription: "Base URL of the BRMS (e.g. `https://initial.gorules.io`).",
 			},
 			"token": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           true,
-				MarkdownDescription: "Personal Access Token (PAT) with appropriate permissions.",
+				MarkdownDescription: "Personal Access Token (PAT) with appropriate permissions. One of `token`, `token_file`, `exec` or `oauth` is required. It can be an ephemeral value (Terraform 1.10+); the provider keeps it in memory only and never copies it into resource state.",
+			},
+			"token_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to a file holding the token. It is re-read on every request, so rotated tokens are picked up.",
+			},
+			"exec": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Credential helper that prints a token on stdout, either as plain text or as JSON `{\"token\": \"...\", \"expires_at\": \"<RFC 3339>\"}` (a Kubernetes `ExecCredential` is also accepted). The token is cached until shortly before it expires.",
+				Attributes: map[string]schema.Attribute{
+					"command": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "Command to run.",
+					},
+					"args": schema.ListAttribute{
+						ElementType:         types.StringType,
+						Optional:            true,
+						MarkdownDescription: "Command arguments.",
+					},
+					"env": schema.MapAttribute{
+						ElementType:         types.StringType,
+						Optional:            true,
+						MarkdownDescription: "Extra environment variables for the command.",
+					},
+				},
+			},
+			"oauth": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "OAuth2 client credentials. The access token is requested from `token_url` and refreshed before it expires.",
+				Attributes: map[string]schema.Attribute{
+					"token_url": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "Token endpoint.",
+					},
+					"client_id": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "Client ID.",
+					},
+					"client_secret": schema.StringAttribute{
+						Required:            true,
+						Sensitive:           true,
+						MarkdownDescription: "Client secret.",
+					},
+					"scopes": schema.ListAttribute{
+						ElementType:         types.StringType,
+						Optional:            true,
+						MarkdownDescription: "Scopes to request.",
+					},
+					"audience": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "`audience` parameter, for identity providers that require it.",
+					},
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Timeout of each API request, in seconds. `0` disables it. Default: `30`.",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "PEM-encoded CA certificate(s) trusted in addition to the system pool, for self-hosted instances behind a private CA.",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to a PEM file with CA certificate(s). Conflicts with `ca_cert_pem`.",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Skip TLS certificate verification. Only for test instances.",
+			},
+			"client_cert_pem": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "PEM-encoded client certificate for mutual TLS. Requires `client_key_pem`.",
+			},
+			"client_key_pem": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "PEM-encoded private key of `client_cert_pem`.",
+			},
+			"client_cert_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to the client certificate for mutual TLS. Requires `client_key_file`.",
+			},
+			"client_key_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path to the private key of `client_cert_file`.",
+			},
+			"extra_headers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Headers added to every API request, e.g. tenant or routing headers required by a gateway. `Authorization`, `Content-Type`, `Accept`, `Host` and `User-Agent` cannot be set.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "HTTP(S) proxy for API calls, e.g. `http://proxy.example.com:3128`. When unset, `HTTPS_PROXY`/`HTTP_PROXY`/`NO_PROXY` apply.",
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Skip the check, run when the provider is configured, that `base_url` is a reachable GoRules BRMS and accepts the credentials. Default: `false`.",
 			},
 		},
 	}
@@ -60,25 +170,112 @@ func (p *gorulesProvider) Configure(ctx context.Context, req pframework.Configur
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// ephemeral values (e.g. a token read from Vault) are unknown during plan
+	// when they depend on resources that do not exist yet
+	credentials := map[string]types.String{"base_url": data.BaseURL, "token": data.Token, "token_file": data.TokenFile}
+	for _, name := range []string{"base_url", "token", "token_file"} {
+		if credentials[name].IsUnknown() {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Unknown "+name,
+				name+" must be known when the provider is configured. When it comes from an ephemeral resource, that resource's arguments cannot depend on resources created in the same apply.")
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	extraHeaders := map[string]string{}
+	if data.ExtraHeaders.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("extra_headers"), "Unknown extra_headers",
+			"extra_headers must be known when the provider is configured; it cannot depend on resources created in the same apply.")
+		return
+	}
+	resp.Diagnostics.Append(data.ExtraHeaders.ElementsAs(ctx, &extraHeaders, false)...)
+	for name := range extraHeaders {
+		if reservedHeaders[http.CanonicalHeaderKey(name)] {
+			resp.Diagnostics.AddAttributeError(path.Root("extra_headers").AtMapKey(name), "Reserved header",
+				fmt.Sprintf("%s is set by the provider and cannot be overridden in extra_headers.", name))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	cfg := &Config{
 		BaseURL: strings.TrimRight(data.BaseURL.ValueString(), "/"),
-		Token:   data.Token.ValueString(),
-		HTTP:    &http.Client{},
 	}
-	if cfg.BaseURL == "" || cfg.Token == "" {
-		resp.Diagnostics.AddError("invalid config", "base_url and token are required")
+	if cfg.BaseURL == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("base_url"), "invalid config", "base_url is required")
+		return
+	}
+
+	transport, timeout, diags := buildTransport(data.transportModel)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// every API call is logged (DEBUG: summary, TRACE: masked headers/bodies)
+	var secrets []string
+	if data.OAuth != nil {
+		secrets = append(secrets, data.OAuth.ClientSecret.ValueString())
+	}
+	unauthenticated := &headerTransport{
+		next:      newLoggingTransport(transport, append(secrets, data.Token.ValueString())...),
+		userAgent: userAgent(p.version, req.TerraformVersion),
+		extra:     extraHeaders,
+	}
+
+	source, diags := buildTokenSource(ctx, data.authModel, &http.Client{Timeout: timeout, Transport: unauthenticated})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	cfg.HTTP = &http.Client{
+		Timeout:   timeout,
+		Transport: &authTransport{next: unauthenticated, source: source},
+	}
+	cfg.Credentials = credentialPath(source)
+	if !data.SkipCredentialsValidation.ValueBool() {
+		cfg.Server, diags = checkServer(ctx, cfg)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 	resp.DataSourceData = cfg
 	resp.ResourceData = cfg
+	resp.ListResourceData = cfg
+	resp.EphemeralResourceData = cfg
 }
 
 func (p *gorulesProvider) Resources(context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
-		NewProjectResource,     // project resource
-		NewEnvironmentResource, // environment resource
-		NewGroupResource,       // group resource
+		NewProjectResource,      // project resource
+		NewEnvironmentResource,  // environment resource
+		NewGroupResource,        // group resource
+		NewDocumentTestResource, // decision test cases
+	}
+}
+
+func (p *gorulesProvider) ListResources(context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewProjectListResource,
+		NewEnvironmentListResource,
+		NewGroupListResource,
+	}
+}
+
+func (p *gorulesProvider) EphemeralResources(context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewAccessTokenEphemeralResource, // short-lived API tokens
 	}
 }
 
 func (p *gorulesProvider) DataSources(context.Context) []func() datasource.DataSource { return nil }
+
+func (p *gorulesProvider) Functions(context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewValidateJDMFunction, // provider::gorules::validate_jdm
+		NewEvaluateFunction,    // provider::gorules::evaluate
+		NewKeyFromNameFunction, // provider::gorules::key_from_name
+	}
+}
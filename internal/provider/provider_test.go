@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+// Acceptance tests run Terraform against an in-process mock of the GoRules
+// API (internal/mockserver). They need TF_ACC=1 and a terraform binary on
+// PATH (or TF_ACC_TERRAFORM_PATH).
+
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"gorules": func() (tfprotov6.ProviderServer, error) {
+		return providerserver.NewProtocol6WithError(New("test"))()
+	},
+}
+
+// testAccServer starts a mock API for the duration of the test
+func testAccServer(t *testing.T, opts ...mockserver.Options) *mockserver.Server {
+	t.Helper()
+	srv := mockserver.New(opts...)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testAccProviderConfig points the provider at srv; append resource blocks to it
+func testAccProviderConfig(srv *mockserver.Server) string {
+	return fmt.Sprintf(`
+provider "gorules" {
+  base_url = %q
+  token    = %q
+}
+`, srv.URL, srv.Token)
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/*.golden files")
+
+// testGolden runs parse on every testdata/<dir>/*.json fixture and compares
+// the JSON-encoded result (or "error: ...") with the matching .golden file
+func testGolden(t *testing.T, dir string, parse func([]byte) (any, error)) {
+	t.Helper()
+	fixtures, err := filepath.Glob(filepath.Join("testdata", dir, "*.json"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures in testdata/%s", dir)
+	}
+	for _, fixture := range fixtures {
+		t.Run(strings.TrimSuffix(filepath.Base(fixture), ".json"), func(t *testing.T) {
+			raw, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []byte
+			if v, err := parse(raw); err != nil {
+				got = []byte("error: " + err.Error() + "\n")
+			} else {
+				got, _ = json.MarshalIndent(v, "", "  ")
+				got = append(got, '\n')
+			}
+
+			golden := strings.TrimSuffix(fixture, ".json") + ".golden"
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("%s mismatch\n got: %s\nwant: %s", golden, got, want)
+			}
+		})
+	}
+}
+
+// testdataSeeds adds every testdata/<dir>/*.json fixture to a fuzz corpus
+func testdataSeeds(f *testing.F, dir string) {
+	fixtures, _ := filepath.Glob(filepath.Join("testdata", dir, "*.json"))
+	for _, fixture := range fixtures {
+		raw, err := os.ReadFile(fixture)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(raw)
+	}
+}
+
+func TestAccProvider_headers(t *testing.T) {
+	srv := testAccServer(t)
+	provider := func(headers string) string {
+		return fmt.Sprintf(`
+provider "gorules" {
+  base_url      = %q
+  token         = %q
+  extra_headers = %s
+}
+
+resource "gorules_project" "test" {
+  name = "Headers"
+}
+`, srv.URL, srv.Token, headers)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      provider(`{ "Authorization" = "Basic abc" }`),
+				ExpectError: regexp.MustCompile(`Reserved header`),
+			},
+			{
+				Config: provider(`{ "X-Tenant" = "acme" }`),
+				Check: func(*terraform.State) error {
+					reqs := srv.Requests()
+					if len(reqs) == 0 {
+						return fmt.Errorf("no requests recorded")
+					}
+					for _, r := range reqs {
+						ua := r.Header.Get("User-Agent")
+						if !regexp.MustCompile(`^terraform-provider-gorules/test terraform/\d+\.\d+\.\d+`).MatchString(ua) {
+							return fmt.Errorf("unexpected User-Agent %q", ua)
+						}
+						if r.Header.Get("X-Tenant") != "acme" {
+							return fmt.Errorf("%s %s: missing X-Tenant header", r.Method, r.Path)
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_tokenFile(t *testing.T) {
+	srv := testAccServer(t)
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte(srv.Token+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf(`
+provider "gorules" {
+  base_url   = %q
+  token_file = %q
+}
+
+resource "gorules_project" "test" {
+  name = "Token File"
+}
+`, srv.URL, file)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttrSet("gorules_project.test", "id"),
+			},
+			// the token is rotated on both sides between runs
+			{
+				PreConfig: func() {
+					srv.SetToken("rotated-token")
+					if err := os.WriteFile(file, []byte("rotated-token"), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccProvider_credentialsValidation(t *testing.T) {
+	srv := testAccServer(t)
+	config := func(skip bool) string {
+		return fmt.Sprintf(`
+provider "gorules" {
+  base_url                    = %q
+  token                       = "wrong-token"
+  skip_credentials_validation = %t
+}
+
+resource "gorules_project" "test" {
+  name = "Credentials"
+}
+`, srv.URL, skip)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// a bad token fails at plan time, before any resource is touched
+			{
+				Config:      config(false),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Provider credentials check failed: 401 Unauthorized`),
+			},
+			{
+				Config:             config(true),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccProvider_ephemeralToken(t *testing.T) {
+	srv := testAccServer(t)
+	p := srv.AddProject(mockserver.Project{Name: "CI", Key: "ci"})
+	// a bootstrap provider mints the token the default provider runs with
+	config := func(projectID string) string {
+		return fmt.Sprintf(`
+provider "gorules" {
+  alias    = "bootstrap"
+  base_url = %[1]q
+  token    = %[2]q
+}
+
+resource "gorules_project" "other" {
+  provider = gorules.bootstrap
+  name     = "Other"
+}
+
+ephemeral "gorules_access_token" "ci" {
+  provider   = gorules.bootstrap
+  project_id = %[3]s
+}
+
+provider "gorules" {
+  base_url = %[1]q
+  token    = ephemeral.gorules_access_token.ci.token
+}
+
+resource "gorules_group" "test" {
+  project_id  = %[4]q
+  name        = "ci"
+  permissions = ["read"]
+}
+`, srv.URL, srv.Token, projectID, p.ID)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			// the token cannot come from a project created in the same apply
+			{
+				Config:      config("gorules_project.other.id"),
+				ExpectError: regexp.MustCompile(`Unknown token`),
+			},
+			{
+				Config: config(fmt.Sprintf("%q", p.ID)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gorules_group.test", "id"),
+					testAccCheckNoTokenInState(srv),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckNoTokenInState checks that group calls were made with a minted
+// access token and that no token sent to the API appears in the state
+func testAccCheckNoTokenInState(srv *mockserver.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		tokens := map[string]bool{}
+		minted := false
+		for _, r := range srv.Requests() {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			tokens[token] = true
+			if strings.Contains(r.Path, "/groups") && strings.HasPrefix(token, "gat_") {
+				minted = true
+			}
+		}
+		if !minted {
+			return fmt.Errorf("groups were not managed with the ephemeral token")
+		}
+		for addr, rs := range s.RootModule().Resources {
+			for name, value := range rs.Primary.Attributes {
+				for token := range tokens {
+					if token != "" && strings.Contains(value, token) {
+						return fmt.Errorf("%s.%s contains an API token", addr, name)
+					}
+				}
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,402 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/jdm"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// -----------------------------------------------------------------------------
+// Resource: gorules_document_test
+//
+// Runs decision test cases during apply. Nothing is created in the BRMS; the
+// resource only exists so deployments can depend on a passing test run.
+// -----------------------------------------------------------------------------
+
+const (
+	documentTestModeLocal  = "local"  // evaluate with the embedded JDM engine
+	documentTestModeRemote = "remote" // evaluate with the BRMS simulator
+
+	documentTestChangedKey = "document_changed" // private data: set by Read
+)
+
+type documentTestResource struct{ cfg *Config }
+
+type documentTestModel struct {
+	ID             types.String            `tfsdk:"id"`
+	ProjectID      types.String            `tfsdk:"project_id"`
+	DocumentID     types.String            `tfsdk:"document_id"`
+	Content        types.String            `tfsdk:"content"`
+	Mode           types.String            `tfsdk:"mode"`
+	FailOnMismatch types.Bool              `tfsdk:"fail_on_mismatch"`
+	Cases          []documentTestCaseModel `tfsdk:"cases"`
+	Total          types.Int64             `tfsdk:"total"`
+	Passed         types.Int64             `tfsdk:"passed"`
+	Failed         types.Int64             `tfsdk:"failed"`
+}
+
+type documentTestCaseModel struct {
+	Name           types.String `tfsdk:"name"`
+	Input          types.String `tfsdk:"input"`           // JSON
+	ExpectedOutput types.String `tfsdk:"expected_output"` // JSON
+}
+
+// Document as returned by GET /api/projects/{id}/documents/{docId}
+type documentItem struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Content json.RawMessage `json:"content"`
+}
+
+type simulateRequest struct {
+	Context any             `json:"context"`
+	Content json.RawMessage `json:"content"`
+}
+
+func NewDocumentTestResource() resource.Resource { return &documentTestResource{} }
+
+func (r *documentTestResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "gorules_document_test"
+}
+
+func (r *documentTestResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.cfg = req.ProviderData.(*Config)
+}
+
+func (r *documentTestResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = rschema.Schema{
+		MarkdownDescription: "Runs decision test cases against a JDM document on apply and fails when outputs don't match.",
+		Attributes: map[string]rschema.Attribute{
+			"id": rschema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Hash of the tested document and cases.",
+			},
+			"project_id": rschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Project ID. Required with `document_id` or `mode = \"remote\"`.",
+			},
+			"document_id": rschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ID of a document stored in the BRMS. Conflicts with `content`.",
+			},
+			"content": rschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "JDM document as a JSON string. Conflicts with `document_id`.",
+			},
+			"mode": rschema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(documentTestModeLocal),
+				MarkdownDescription: "Where cases are evaluated: `local` (embedded engine, default) or `remote` (BRMS simulator).",
+			},
+			"fail_on_mismatch": rschema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "If true (default), any failing case fails the apply. If false, failures are reported as warnings and only counted.",
+			},
+			"cases": rschema.ListNestedAttribute{
+				Required:            true,
+				MarkdownDescription: "Test cases.",
+				NestedObject: rschema.NestedAttributeObject{
+					Attributes: map[string]rschema.Attribute{
+						"name": rschema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Case name used in failure messages.",
+						},
+						"input": rschema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Input context as JSON (use `jsonencode`).",
+						},
+						"expected_output": rschema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Expected output as JSON. Objects match as a subset: only the keys listed are compared.",
+						},
+					},
+				},
+			},
+			"total": rschema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of cases run.",
+			},
+			"passed": rschema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of passing cases.",
+			},
+			"failed": rschema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of failing cases.",
+			},
+		},
+	}
+}
+
+func (r *documentTestResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg documentTestModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hasContent := !cfg.Content.IsNull()
+	hasDocument := !cfg.DocumentID.IsNull()
+	if hasContent && hasDocument {
+		resp.Diagnostics.AddAttributeError(path.Root("content"), "Conflicting document reference",
+			"Set either content or document_id, not both.")
+	}
+	if !hasContent && !hasDocument {
+		resp.Diagnostics.AddAttributeError(path.Root("content"), "Missing document reference",
+			"Set content (JDM JSON) or project_id + document_id.")
+	}
+	if !cfg.Mode.IsNull() && !cfg.Mode.IsUnknown() {
+		if m := cfg.Mode.ValueString(); m != documentTestModeLocal && m != documentTestModeRemote {
+			resp.Diagnostics.AddAttributeError(path.Root("mode"), "Invalid mode",
+				fmt.Sprintf("mode must be %q or %q, got %q", documentTestModeLocal, documentTestModeRemote, m))
+		}
+	}
+	needsProject := hasDocument || (!cfg.Mode.IsUnknown() && cfg.Mode.ValueString() == documentTestModeRemote)
+	if needsProject && cfg.ProjectID.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("project_id"), "Missing project_id",
+			"project_id is required when using document_id or mode = \"remote\".")
+	}
+}
+
+func (r *documentTestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan documentTestModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.run(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the last run. Documents stored in the BRMS are fetched again: a
+// deleted document removes the resource from state, a changed one is recorded
+// in private state so ModifyPlan plans a re-run.
+func (r *documentTestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state documentTestModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.DocumentID.IsNull() && r.cfg != nil {
+		content, err := r.documentContent(ctx, &state)
+		if isNotFound(err) {
+			tflog.Warn(ctx, "tested document not found, removing from state", map[string]interface{}{"document_id": state.DocumentID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Load Document", err, nil)
+			return
+		}
+		// a document reverted to the tested version needs no re-run
+		var changed []byte
+		if documentTestID(&state, content) != state.ID.ValueString() {
+			changed = []byte(`true`)
+		}
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, documentTestChangedKey, changed)...)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ModifyPlan plans a re-run when Read found that the stored document changed;
+// changes to content or cases already show up as a diff
+func (r *documentTestResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	changed, diags := req.Private.GetKey(ctx, documentTestChangedKey)
+	resp.Diagnostics.Append(diags...)
+	if string(changed) != "true" {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	for _, name := range []string{"total", "passed", "failed"} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.Int64Unknown())...)
+	}
+}
+
+// Update re-runs every case with the new document/cases
+func (r *documentTestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan documentTestModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.run(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, documentTestChangedKey, nil)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *documentTestResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
+
+// -----------------------------------------------------------------------------
+// Test run
+// -----------------------------------------------------------------------------
+
+// run evaluates every case and fills id/total/passed/failed in m
+func (r *documentTestResource) run(ctx context.Context, m *documentTestModel, diags *diag.Diagnostics) {
+	content, err := r.documentContent(ctx, m)
+	if err != nil {
+		addAPIError(diags, "Load Document", err, nil)
+		return
+	}
+
+	var failures []string
+	passed := 0
+	for i, c := range m.Cases {
+		label := fmt.Sprintf("#%d", i+1)
+		if !c.Name.IsNull() && c.Name.ValueString() != "" {
+			label = fmt.Sprintf("%q (#%d)", c.Name.ValueString(), i+1)
+		}
+
+		var input, expected any
+		if err := json.Unmarshal([]byte(c.Input.ValueString()), &input); err != nil {
+			diags.AddError("Invalid test case input", fmt.Sprintf("case %s: %s", label, err))
+			return
+		}
+		if err := json.Unmarshal([]byte(c.ExpectedOutput.ValueString()), &expected); err != nil {
+			diags.AddError("Invalid test case expected_output", fmt.Sprintf("case %s: %s", label, err))
+			return
+		}
+
+		actual, err := r.evaluate(ctx, m, content, input)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("case %s: evaluation failed: %s", label, err))
+			continue
+		}
+		if diffs := jdm.Diff(expected, actual); len(diffs) > 0 {
+			failures = append(failures, fmt.Sprintf("case %s:\n  %s", label, strings.Join(diffs, "\n  ")))
+			continue
+		}
+		passed++
+	}
+
+	m.ID = types.StringValue(documentTestID(m, content))
+	m.Total = types.Int64Value(int64(len(m.Cases)))
+	m.Passed = types.Int64Value(int64(passed))
+	m.Failed = types.Int64Value(int64(len(failures)))
+
+	if len(failures) > 0 {
+		summary := fmt.Sprintf("%d of %d decision test cases failed", len(failures), len(m.Cases))
+		if m.FailOnMismatch.ValueBool() {
+			diags.AddError(summary, strings.Join(failures, "\n"))
+		} else {
+			diags.AddWarning(summary, strings.Join(failures, "\n"))
+		}
+	}
+}
+
+// documentContent returns the JDM under test, fetching it when referenced by ID
+func (r *documentTestResource) documentContent(ctx context.Context, m *documentTestModel) ([]byte, error) {
+	if !m.Content.IsNull() && !m.Content.IsUnknown() {
+		return []byte(m.Content.ValueString()), nil
+	}
+	if r.cfg == nil {
+		return nil, fmt.Errorf("provider not configured: document_id requires base_url/token")
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/documents/%s", r.cfg.BaseURL, m.ProjectID.ValueString(), m.DocumentID.ValueString())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := apiClient(r.cfg.HTTP).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	if err := checkResponse(res, raw); err != nil {
+		return nil, err
+	}
+
+	var doc documentItem
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing document: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("document %s has no content", m.DocumentID.ValueString())
+	}
+	return doc.Content, nil
+}
+
+func (r *documentTestResource) evaluate(ctx context.Context, m *documentTestModel, content []byte, input any) (any, error) {
+	if m.Mode.ValueString() != documentTestModeRemote {
+		doc, err := jdm.Parse(content)
+		if err != nil {
+			return nil, err
+		}
+		return jdm.Evaluate(doc, input)
+	}
+	if r.cfg == nil {
+		return nil, fmt.Errorf("provider not configured: mode = \"remote\" requires base_url/token")
+	}
+	if !r.cfg.Server.Supports(FeatureSimulate) {
+		return nil, fmt.Errorf("mode = \"remote\" is not supported by this BRMS (version %q); use mode = \"local\"", r.cfg.Server.Version)
+	}
+
+	b, _ := json.Marshal(simulateRequest{Context: input, Content: content})
+	url := fmt.Sprintf("%s/api/projects/%s/simulate", r.cfg.BaseURL, m.ProjectID.ValueString())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := apiClient(r.cfg.HTTP).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	if err := checkResponse(res, raw); err != nil {
+		return nil, fmt.Errorf("simulate: %w", err)
+	}
+
+	// simulator answers { "result": {...}, "trace": {...} }
+	var out struct {
+		Result any `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("error parsing simulate response: %w", err)
+	}
+	return out.Result, nil
+}
+
+// documentTestID is stable for the same document and cases
+func documentTestID(m *documentTestModel, content []byte) string {
+	h := sha256.New()
+	h.Write(content)
+	for _, c := range m.Cases {
+		fmt.Fprintf(h, "\x00%s\x00%s\x00%s", c.Name.ValueString(), c.Input.ValueString(), c.ExpectedOutput.ValueString())
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
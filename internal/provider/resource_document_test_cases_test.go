@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+// testDiscountJDM gives `big` to carts of 100 or more and `small` otherwise
+func testDiscountJDM(big, small string) string {
+	return fmt.Sprintf(`{"nodes":[
+  {"id":"in","type":"inputNode","name":"request"},
+  {"id":"t","type":"decisionTableNode","name":"discounts","content":{"hitPolicy":"first",
+    "inputs":[{"id":"i1","field":"cart.total"}],
+    "outputs":[{"id":"o1","field":"discount"}],
+    "rules":[{"_id":"r1","i1":">= 100","o1":%q},{"_id":"r2","i1":"","o1":%q}]}},
+  {"id":"out","type":"outputNode","name":"response"}
+],"edges":[{"id":"e1","sourceId":"in","targetId":"t"},{"id":"e2","sourceId":"t","targetId":"out"}]}`, big, small)
+}
+
+const testAccDocumentTestCases = `
+  cases = [
+    {
+      name            = "big cart"
+      input           = jsonencode({ cart = { total = 150 } })
+      expected_output = jsonencode({ discount = 0.1 })
+    },
+    {
+      name            = "small cart"
+      input           = jsonencode({ cart = { total = 10 } })
+      expected_output = jsonencode({ discount = 0 })
+    },
+  ]`
+
+func testAccDocumentTestConfig(srv *mockserver.Server, body string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "gorules_document_test" "test" {
+  %s
+%s
+}
+`, body, testAccDocumentTestCases)
+}
+
+// testAccStoredDocument seeds a project with the discount document
+func testAccStoredDocument(srv *mockserver.Server) (projectID, documentID string) {
+	p := srv.AddProject(mockserver.Project{Name: "Document Tests", Key: "document-tests"})
+	d := srv.AddDocument(mockserver.Document{ProjectID: p.ID, Name: "discounts", Type: "decision",
+		Content: json.RawMessage(testDiscountJDM("0.1", "0"))})
+	return p.ID, d.ID
+}
+
+func TestAccDocumentTest_local(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDocumentTestConfig(srv, fmt.Sprintf("content = %q", testDiscountJDM("0.1", "0"))),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_document_test.test", "mode", "local"),
+					resource.TestCheckResourceAttr("gorules_document_test.test", "total", "2"),
+					resource.TestCheckResourceAttr("gorules_document_test.test", "passed", "2"),
+					resource.TestCheckResourceAttr("gorules_document_test.test", "failed", "0"),
+					resource.TestCheckResourceAttrSet("gorules_document_test.test", "id"),
+				),
+			},
+			{
+				// failures are only counted
+				Config: testAccDocumentTestConfig(srv, fmt.Sprintf(`content          = %q
+  fail_on_mismatch = false`, testDiscountJDM("0.2", "0"))),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_document_test.test", "passed", "1"),
+					resource.TestCheckResourceAttr("gorules_document_test.test", "failed", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDocumentTest_failBlocksApply(t *testing.T) {
+	srv := testAccServer(t)
+
+	// the project depends on the test run, so it is never created
+	config := testAccDocumentTestConfig(srv, fmt.Sprintf("content = %q", testDiscountJDM("0.2", "0"))) + `
+resource "gorules_project" "test" {
+  name       = "Released"
+  key        = "released"
+  depends_on = [gorules_document_test.test]
+}
+`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`(?s)1 of 2 decision test cases failed.*"big cart".*discount`),
+			},
+		},
+	})
+	if n := srv.CountRequests("POST", "/api/projects"); n != 0 {
+		t.Errorf("project created %d times after a failed test run", n)
+	}
+}
+
+func TestAccDocumentTest_remote(t *testing.T) {
+	srv := testAccServer(t)
+	pid, did := testAccStoredDocument(srv)
+	stored := fmt.Sprintf(`project_id  = %q
+  document_id = %q`, pid, did)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the stored document is evaluated locally by default
+				Config: testAccDocumentTestConfig(srv, stored),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_document_test.test", "passed", "2"),
+					testAccCheckSimulateCalls(srv, pid, 0),
+				),
+			},
+			{
+				Config: testAccDocumentTestConfig(srv, stored+`
+  mode        = "remote"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_document_test.test", "passed", "2"),
+					testAccCheckSimulateCalls(srv, pid, 2),
+				),
+			},
+			{
+				// an edit in the BRMS plans a re-run
+				PreConfig: func() {
+					srv.UpdateDocument(pid, did, func(d *mockserver.Document) {
+						d.Content = json.RawMessage(testDiscountJDM("0.2", "0"))
+					})
+				},
+				Config: testAccDocumentTestConfig(srv, stored+`
+  mode        = "remote"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_document_test.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ExpectError: regexp.MustCompile(`1 of 2 decision test cases failed`),
+			},
+			{
+				// reverted to the tested version: the failed run left the
+				// state untouched, so nothing needs to run again
+				PreConfig: func() {
+					srv.ResetRequests()
+					srv.UpdateDocument(pid, did, func(d *mockserver.Document) {
+						d.Content = json.RawMessage(testDiscountJDM("0.1", "0"))
+					})
+				},
+				Config: testAccDocumentTestConfig(srv, stored+`
+  mode        = "remote"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_document_test.test", "passed", "2"),
+					testAccCheckSimulateCalls(srv, pid, 0),
+				),
+			},
+			{
+				// unchanged document: nothing to do
+				Config: testAccDocumentTestConfig(srv, stored+`
+  mode        = "remote"`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testAccCheckSimulateCalls checks how many cases ran in the BRMS simulator
+// since the last check
+func testAccCheckSimulateCalls(srv *mockserver.Server, projectID string, want int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		got := srv.CountRequests("POST", "/api/projects/"+projectID+"/simulate")
+		srv.ResetRequests()
+		if got != want {
+			return fmt.Errorf("%d simulate requests, want %d", got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccDocumentTest_missingDocument(t *testing.T) {
+	srv := testAccServer(t)
+	pid, did := testAccStoredDocument(srv)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDocumentTestConfig(srv, fmt.Sprintf("project_id  = %q\n  document_id = \"missing\"", pid)),
+				ExpectError: regexp.MustCompile(`(?s)Load Document.*document not found`),
+			},
+			{
+				Config: testAccDocumentTestConfig(srv, fmt.Sprintf("project_id  = %q\n  document_id = %q", pid, did)),
+				Check:  resource.TestCheckResourceAttr("gorules_document_test.test", "passed", "2"),
+			},
+			{
+				// deleted in the BRMS: dropped from state on refresh
+				PreConfig:          func() { srv.RemoveDocument(pid, did) },
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: func(s *terraform.State) error {
+					if _, ok := s.RootModule().Resources["gorules_document_test.test"]; ok {
+						return fmt.Errorf("document test still in state")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -9,10 +9,12 @@ import (
 	"io"
 	"net/http"
 	"sort"
-	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -32,6 +34,7 @@ type environmentModel struct {
 	Type           types.String   `tfsdk:"type"`
 	ApprovalMode   types.String   `tfsdk:"approval_mode"`
 	ApprovalGroups []types.String `tfsdk:"approval_groups"` // Group NAMES (not IDs)
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewEnvironmentResource() resource.Resource { return &environmentResource{} }
@@ -40,6 +43,15 @@ func (r *environmentResource) Metadata(_ context.Context, _ resource.MetadataReq
 	resp.TypeName = "gorules_environment"
 }
 
+func (r *environmentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = projectChildIdentitySchema("environment")
+}
+
+// ImportState accepts "<project_id>/<environment_id>" or an identity {project_id, id}
+func (r *environmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateProjectChild(ctx, req, resp)
+}
+
 func (r *environmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -47,8 +59,9 @@ func (r *environmentResource) Configure(_ context.Context, req resource.Configur
 	r.cfg = req.ProviderData.(*Config)
 }
 
-func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *environmentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = rschema.Schema{
+		Version:             environmentSchemaVersion,
 		MarkdownDescription: "Manages environments for a project in GoRules.",
 		Attributes: map[string]rschema.Attribute{
 			"id": rschema.StringAttribute{
@@ -60,7 +73,10 @@ func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest
 			},
 			"project_id": rschema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "Parent project ID.",
+				MarkdownDescription: "Parent project ID. Changing it replaces the environment.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"name": rschema.StringAttribute{
 				Required:            true,
@@ -68,7 +84,11 @@ func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest
 			},
 			"key": rschema.StringAttribute{
 				Optional:            true,
+				Computed:            true,
 				MarkdownDescription: "Environment key (if not provided, uses name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"type": rschema.StringAttribute{
 				Required:            true, // enum: brms | deployment
@@ -82,8 +102,15 @@ func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest
 				ElementType:         types.StringType,
 				Optional:            true,
 				Computed:            true, // allows default to []
+				Default:             listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
 				MarkdownDescription: "List of group NAMES that approve.",
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -109,8 +136,13 @@ type envItem struct {
 	RawApprovalGroups json.RawMessage `json:"approvalGroups,omitempty"` // For dynamic parsing
 }
 
-// UnmarshalJSON implements custom parsing to handle approvalGroups dynamically
+// UnmarshalJSON decodes an environment and normalizes approvalGroups, which
+// the API returns either as group IDs or as {id, name} objects
 func (e *envItem) UnmarshalJSON(data []byte) error {
+	if kind := jsonKind(data); kind != "object" {
+		return fmt.Errorf("environment: expected object, got %s", kind)
+	}
+
 	// Define an alias to avoid infinite recursion
 	type Alias envItem
 	aux := &struct {
@@ -118,38 +150,60 @@ func (e *envItem) UnmarshalJSON(data []byte) error {
 	}{
 		Alias: (*Alias)(e),
 	}
-
 	if err := json.Unmarshal(data, &aux); err != nil {
-		return err
-	}
-
-	// Process approvalGroups dynamically
-	if e.RawApprovalGroups != nil {
-		// Try first as string array
-		var stringArray []string
-		if err := json.Unmarshal(e.RawApprovalGroups, &stringArray); err == nil {
-			e.ApprovalGroups = stringArray
-		} else {
-			// Try as object array
-			var objectArray []approvalGroup
-			if err := json.Unmarshal(e.RawApprovalGroups, &objectArray); err == nil {
-				e.ApprovalGroups = make([]string, len(objectArray))
-				for i, obj := range objectArray {
-					e.ApprovalGroups[i] = obj.ID
-				}
-			} else {
-				// If unable to parse, initialize empty array
-				e.ApprovalGroups = []string{}
-			}
-		}
-	} else {
-		e.ApprovalGroups = []string{}
+		return fmt.Errorf("environment: %w", err)
 	}
 
+	groups, err := parseApprovalGroups(e.RawApprovalGroups)
+	if err != nil {
+		return fmt.Errorf("environment %q: %w", e.ID, err)
+	}
+	e.ApprovalGroups = groups
 	return nil
 }
 
-// Payload para POST/PUT
+// parseApprovalGroups returns the group IDs of an approvalGroups member.
+// Missing or null means no groups; each element must be a non-empty ID
+// string or an object with a non-empty "id".
+func parseApprovalGroups(raw json.RawMessage) ([]string, error) {
+	switch kind := jsonKind(raw); kind {
+	case "missing", "null":
+		return []string{}, nil
+	case "array":
+	default:
+		return nil, fmt.Errorf("approvalGroups: expected array, got %s", kind)
+	}
+
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil, fmt.Errorf("approvalGroups: %w", err)
+	}
+	ids := make([]string, 0, len(elems))
+	for i, el := range elems {
+		var id string
+		switch kind := jsonKind(el); kind {
+		case "string":
+			if err := json.Unmarshal(el, &id); err != nil {
+				return nil, fmt.Errorf("approvalGroups[%d]: %w", i, err)
+			}
+		case "object":
+			var obj approvalGroup
+			if err := json.Unmarshal(el, &obj); err != nil {
+				return nil, fmt.Errorf("approvalGroups[%d]: %w", i, err)
+			}
+			id = obj.ID
+		default:
+			return nil, fmt.Errorf("approvalGroups[%d]: expected string or object, got %s", i, kind)
+		}
+		if id == "" {
+			return nil, fmt.Errorf("approvalGroups[%d]: empty group id", i)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Payload for POST/PUT
 type envCreateUpdateRequest struct {
 	Name           string   `json:"name"`
 	Key            *string  `json:"key,omitempty"`
@@ -159,25 +213,24 @@ type envCreateUpdateRequest struct {
 }
 
 // -----------------------------------------------------------------------------
-// Helpers de API (LIST + find by ID dentro del listado)
+// API helpers (LIST + find by ID within the listing)
 // -----------------------------------------------------------------------------
 
 func (r *environmentResource) listEnvironments(ctx context.Context, projectID string) ([]envItem, int, []byte, error) {
 	url := fmt.Sprintf("%s/api/projects/%s/environments", r.cfg.BaseURL, projectID)
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	req.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 
-	res, err := clientNoRedirect(r.cfg.HTTP).Do(req)
+	res, err := apiClient(r.cfg.HTTP).Do(req)
 	if err != nil {
 		return nil, 0, nil, err
 	}
 	defer res.Body.Close()
 	raw, _ := io.ReadAll(res.Body)
-	if res.StatusCode >= 300 {
-		return nil, res.StatusCode, raw, fmt.Errorf("status=%d", res.StatusCode)
+	if err := checkResponse(res, raw); err != nil {
+		return nil, res.StatusCode, raw, err
 	}
 
-	var arr []envItem // la API entrega un ARRAY
+	var arr []envItem // the API returns an ARRAY
 	if err := json.Unmarshal(raw, &arr); err != nil {
 		return nil, res.StatusCode, raw, err
 	}
@@ -191,15 +244,31 @@ func (r *environmentResource) findEnvironmentByID(ctx context.Context, projectID
 	}
 	for _, it := range arr {
 		if it.ID == envID {
-			// normalizamos arrays para consistencia
-			sort.Strings(it.ApprovalGroups) // son IDs aquí
+			// normalize arrays for consistency
+			sort.Strings(it.ApprovalGroups) // these are IDs here
 			return &it, code, raw, nil
 		}
 	}
-	// No hay GET by ID; simulamos 404 si no aparece
+	// There is no GET by ID; report 404 when it is not listed
 	return nil, http.StatusNotFound, raw, fmt.Errorf("environment not found in listing")
 }
 
+// waitListed waits until the listing shows want (see waitForListing)
+func (r *environmentResource) waitListed(ctx context.Context, projectID string, want envItem) error {
+	return waitForListing(ctx, "environment "+want.ID, func(ctx context.Context) (bool, error) {
+		items, _, _, err := r.listEnvironments(ctx, projectID)
+		if err != nil {
+			return false, err
+		}
+		for _, it := range items {
+			if it.ID == want.ID {
+				return it.Name == want.Name && it.Key == want.Key && it.Type == want.Type, nil
+			}
+		}
+		return false, nil
+	})
+}
+
 // -----------------------------------------------------------------------------
 // Create
 // -----------------------------------------------------------------------------
@@ -216,6 +285,14 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// default key = name
 	var keyPtr *string
 	if !plan.Key.IsNull() && !plan.Key.IsUnknown() && plan.Key.ValueString() != "" {
@@ -259,19 +336,17 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 	b, _ := json.Marshal(body)
 	url := fmt.Sprintf("%s/api/projects/%s/environments", r.cfg.BaseURL, plan.ProjectID.ValueString())
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	res, err := clientNoRedirect(r.cfg.HTTP).Do(httpReq)
+	res, err := apiClient(r.cfg.HTTP).Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating Environment", err.Error())
+		addAPIError(&resp.Diagnostics, "Create Environment", err, nil)
 		return
 	}
 	defer res.Body.Close()
 	raw, _ := io.ReadAll(res.Body)
-	if res.StatusCode >= 300 {
-		resp.Diagnostics.AddError("Create Environment falló",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	if err := checkResponse(res, raw); err != nil {
+		addAPIError(&resp.Diagnostics, "Create Environment", err, environmentFields)
 		return
 	}
 
@@ -281,11 +356,11 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	// IDs → NOMBRES para guardar en state igual que el plan
+	// IDs → NAMES, stored in state like the plan
 	namesBack, err := ResolveGroupNamesByID(ctx, r.cfg, plan.ProjectID.ValueString(), created.ApprovalGroups)
 	if err != nil {
 		resp.Diagnostics.AddWarning("Could not resolve group names from IDs", err.Error())
-		// en caso de error, usamos lo del plan
+		// fall back to the plan
 		namesBack = names
 	}
 	sort.Strings(namesBack)
@@ -296,7 +371,8 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 		Name:           types.StringValue(created.Name),
 		Key:            types.StringValue(created.Key),
 		Type:           types.StringValue(created.Type),
-		ApprovalGroups: ToTFStringList(namesBack), // NOMBRES en state
+		ApprovalGroups: ToTFStringListKeepOrder(namesBack, plan.ApprovalGroups), // NAMES in state
+		Timeouts:       plan.Timeouts,
 	}
 	if created.ApprovalMode != nil {
 		state.ApprovalMode = types.StringValue(*created.ApprovalMode)
@@ -307,6 +383,11 @@ func (r *environmentResource) Create(ctx context.Context, req resource.CreateReq
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectChildIdentityModel{ProjectID: state.ProjectID, ID: state.ID})...)
+	if err := r.waitListed(ctx, plan.ProjectID.ValueString(), created); err != nil {
+		resp.Diagnostics.AddError("Environment not listed after create",
+			err.Error()+"\n\nThe environment was created and is kept in state as tainted; the next apply replaces it.")
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -323,25 +404,34 @@ func (r *environmentResource) Read(ctx context.Context, req resource.ReadRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// identity comes from state; set it first so it survives the early returns below
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectChildIdentityModel{ProjectID: state.ProjectID, ID: state.ID})...)
 
-	found, code, raw, err := r.findEnvironmentByID(ctx, state.ProjectID.ValueString(), state.ID.ValueString())
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	found, code, _, err := r.findEnvironmentByID(ctx, state.ProjectID.ValueString(), state.ID.ValueString())
 	if err != nil {
+		// Create/Update wait for the listing, so a miss means it was deleted
 		if code == http.StatusNotFound {
-			resp.Diagnostics.AddWarning("Get Environment not found (se conserva estado)",
-				"No se halló el environment en la lista; se conserva el estado para evitar recreación por error.")
+			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Get Environment falló",
-			fmt.Sprintf("status=%d\nbody=%s", code, string(raw)))
+		addAPIError(&resp.Diagnostics, "Read Environment", err, nil)
 		return
 	}
 
-	// IDs → NOMBRES para state
+	// IDs → NAMES for state
 	names, err := ResolveGroupNamesByID(ctx, r.cfg, state.ProjectID.ValueString(), found.ApprovalGroups)
 	if err != nil {
-		// mantenemos lo que ya había en state
+		// keep what is already in state
 		resp.Diagnostics.AddWarning("Could not resolve group names from IDs",
-			"Se conserva el valor actual en estado.")
+			err.Error()+"\n\napproval_groups keeps its current value in state.")
 		names = make([]string, 0, len(state.ApprovalGroups))
 		for _, s := range state.ApprovalGroups {
 			if !s.IsNull() && !s.IsUnknown() && s.ValueString() != "" {
@@ -359,7 +449,7 @@ func (r *environmentResource) Read(ctx context.Context, req resource.ReadRequest
 	} else {
 		state.ApprovalMode = types.StringNull()
 	}
-	state.ApprovalGroups = ToTFStringList(names)
+	state.ApprovalGroups = ToTFStringListKeepOrder(names, state.ApprovalGroups)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -380,6 +470,14 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// default key = name
 	var keyPtr *string
 	if !plan.Key.IsNull() && !plan.Key.IsUnknown() && plan.Key.ValueString() != "" {
@@ -423,19 +521,17 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 	b, _ := json.Marshal(body)
 	url := fmt.Sprintf("%s/api/projects/%s/environments/%s", r.cfg.BaseURL, plan.ProjectID.ValueString(), plan.ID.ValueString())
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(b))
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	res, err := clientNoRedirect(r.cfg.HTTP).Do(httpReq)
+	res, err := apiClient(r.cfg.HTTP).Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating Environment", err.Error())
+		addAPIError(&resp.Diagnostics, "Update Environment", err, nil)
 		return
 	}
 	defer res.Body.Close()
 	raw, _ := io.ReadAll(res.Body)
-	if res.StatusCode >= 300 {
-		resp.Diagnostics.AddError("Update Environment falló",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	if err := checkResponse(res, raw); err != nil {
+		addAPIError(&resp.Diagnostics, "Update Environment", err, environmentFields)
 		return
 	}
 
@@ -445,7 +541,7 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	// IDs → NOMBRES
+	// IDs → NAMES
 	namesBack, err := ResolveGroupNamesByID(ctx, r.cfg, plan.ProjectID.ValueString(), updated.ApprovalGroups)
 	if err != nil {
 		resp.Diagnostics.AddWarning("Could not resolve group names from IDs", err.Error())
@@ -459,7 +555,8 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 		Name:           types.StringValue(updated.Name),
 		Key:            types.StringValue(updated.Key),
 		Type:           types.StringValue(updated.Type),
-		ApprovalGroups: ToTFStringList(namesBack),
+		ApprovalGroups: ToTFStringListKeepOrder(namesBack, plan.ApprovalGroups),
+		Timeouts:       plan.Timeouts,
 	}
 	if updated.ApprovalMode != nil {
 		state.ApprovalMode = types.StringValue(*updated.ApprovalMode)
@@ -468,10 +565,14 @@ func (r *environmentResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectChildIdentityModel{ProjectID: state.ProjectID, ID: state.ID})...)
+	if err := r.waitListed(ctx, plan.ProjectID.ValueString(), updated); err != nil {
+		resp.Diagnostics.AddError("Environment update not listed", err.Error())
+	}
 }
 
 // -----------------------------------------------------------------------------
-// Delete (con reintentos para 5xx)
+// Delete (with retries for 5xx)
 // -----------------------------------------------------------------------------
 
 func (r *environmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -485,32 +586,37 @@ func (r *environmentResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	url := fmt.Sprintf("%s/api/projects/%s/environments/%s", r.cfg.BaseURL, state.ProjectID.ValueString(), state.ID.ValueString())
 
-	var lastStatus int
-	var lastBody []byte
-	for i := 0; i < 3; i++ {
+	// 5xx/429/network errors are retried with backoff until the delete timeout
+	for attempt := 0; ; attempt++ {
 		httpReq, _ := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
-		httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 
-		res, err := clientNoRedirect(r.cfg.HTTP).Do(httpReq)
-		if err != nil {
-			lastStatus = 0
-			lastBody = []byte(err.Error())
-		} else {
-			func() {
-				defer res.Body.Close()
-				lastStatus = res.StatusCode
-				lastBody, _ = io.ReadAll(res.Body)
-			}()
+		res, err := apiClient(r.cfg.HTTP).Do(httpReq)
+		if err == nil {
+			raw, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			err = checkResponse(res, raw)
 		}
-		if lastStatus == http.StatusNotFound || (lastStatus >= 200 && lastStatus < 300) {
+		if err == nil || isNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		time.Sleep(400 * time.Millisecond) // backoff simple
+		if !retryable(err) || sleepCtx(ctx, backoff(attempt)) != nil {
+			action := "Delete Environment"
+			if attempt > 0 {
+				action = fmt.Sprintf("Delete Environment (after %d attempts)", attempt+1)
+			}
+			addAPIError(&resp.Diagnostics, action, err, nil)
+			return
+		}
 	}
-
-	resp.Diagnostics.AddError("Delete Environment no confirmado tras reintentos",
-		fmt.Sprintf("último status=%d body=%s", lastStatus, string(lastBody)))
 }
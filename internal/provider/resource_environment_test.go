@@ -0,0 +1,332 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+func testAccEnvironmentConfig(srv *mockserver.Server, projectKey, body string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "gorules_project" "test" {
+  name = "Env Tests"
+  key  = %q
+}
+
+resource "gorules_group" "zeta" {
+  project_id  = gorules_project.test.id
+  name        = "zeta"
+  permissions = []
+}
+
+resource "gorules_group" "alpha" {
+  project_id  = gorules_project.test.id
+  name        = "alpha"
+  permissions = []
+}
+
+resource "gorules_environment" "test" {
+  project_id = gorules_project.test.id
+  %s
+}
+`, projectKey, body)
+}
+
+func TestAccEnvironment_basic(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentConfig(srv, "env-tests", `name = "staging"
+  type = "brms"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gorules_environment.test", "id"),
+					resource.TestCheckResourceAttr("gorules_environment.test", "key", "staging"),
+					resource.TestCheckResourceAttr("gorules_environment.test", "approval_groups.#", "0"),
+					resource.TestCheckNoResourceAttr("gorules_environment.test", "approval_mode"),
+				),
+			},
+			// groups listed out of alphabetical order must not cause a diff
+			{
+				Config: testAccEnvironmentConfig(srv, "env-tests", `name            = "production"
+  key             = "staging"
+  type            = "deployment"
+  approval_mode   = "require_any"
+  approval_groups = [gorules_group.zeta.name, gorules_group.alpha.name]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_environment.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_environment.test", "name", "production"),
+					resource.TestCheckResourceAttr("gorules_environment.test", "approval_groups.0", "zeta"),
+					resource.TestCheckResourceAttr("gorules_environment.test", "approval_groups.1", "alpha"),
+				),
+			},
+			{
+				ResourceName:      "gorules_environment.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccProjectChildImportID("gorules_environment.test"),
+				// imported lists come back in API order
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"approval_groups"},
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_approvalGroupsAsObjects(t *testing.T) {
+	srv := testAccServer(t, mockserver.Options{ApprovalGroupsAsObjects: true})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentConfig(srv, "env-objects", `name            = "prod"
+  type            = "deployment"
+  approval_groups = [gorules_group.alpha.name]`),
+				Check: resource.TestCheckResourceAttr("gorules_environment.test", "approval_groups.0", "alpha"),
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_replaceOnProjectChange(t *testing.T) {
+	srv := testAccServer(t)
+	other := srv.AddProject(mockserver.Project{Name: "Other", Key: "other"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentConfig(srv, "env-move", `name = "qa"
+  type = "brms"`),
+			},
+			{
+				Config: testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "gorules_environment" "test" {
+  project_id = %q
+  name       = "qa"
+  type       = "brms"
+}
+`, other.ID),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_environment.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("gorules_environment.test", "project_id", other.ID),
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_driftOutOfBand(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccEnvironmentConfig(srv, "env-drift", `name = "qa"
+  type = "brms"`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					for _, p := range srv.Projects() {
+						for _, e := range srv.Environments(p.ID) {
+							srv.UpdateEnvironment(p.ID, e.ID, func(e *mockserver.Environment) { e.Type = "deployment" })
+						}
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_environment.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("gorules_environment.test", "type", "brms"),
+			},
+		},
+	})
+}
+
+// testAccProjectChildImportID builds the "<project_id>/<id>" import ID
+// listings that lag behind writes: Create/Update wait, so plans stay empty
+func TestAccEnvironment_listingLag(t *testing.T) {
+	srv := testAccServer(t, mockserver.Options{ListingLag: 2})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentConfig(srv, "env-lag", `name            = "staging"
+  type            = "brms"
+  approval_groups = [gorules_group.alpha.name]`),
+				Check: resource.TestCheckResourceAttr("gorules_environment.test", "approval_groups.0", "alpha"),
+			},
+			{
+				Config: testAccEnvironmentConfig(srv, "env-lag", `name            = "production"
+  key             = "staging"
+  type            = "deployment"
+  approval_groups = [gorules_group.alpha.name]`),
+				Check: resource.TestCheckResourceAttr("gorules_environment.test", "type", "deployment"),
+			},
+		},
+	})
+}
+
+// an environment missing from the listing was deleted and is planned again
+func TestAccEnvironment_deletedOutOfBand(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccEnvironmentConfig(srv, "env-gone", `name = "qa"
+  type = "brms"`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{Config: config},
+			{
+				PreConfig: func() {
+					for _, p := range srv.Projects() {
+						for _, e := range srv.Environments(p.ID) {
+							srv.RemoveEnvironment(p.ID, e.ID)
+						}
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_environment.test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+// a transient 503 on delete is retried with backoff
+func TestAccEnvironment_deleteRetries(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccEnvironmentConfig(srv, "env-retries", `name = "staging"
+  type = "brms"`)
+	var projectID, envID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if n := srv.CountRequests("DELETE", "/api/projects/"+projectID+"/environments/"+envID); n != 3 {
+				return fmt.Errorf("expected 3 DELETE attempts, got %d", n)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCaptureID("gorules_project.test", &projectID),
+					testAccCaptureID("gorules_environment.test", &envID),
+				),
+			},
+			{
+				PreConfig: func() {
+					srv.Inject(mockserver.Fault{Method: "DELETE", PathPrefix: "/api/projects/" + projectID + "/environments/", Status: 503, Times: 2})
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_identity(t *testing.T) {
+	srv := testAccServer(t)
+	checks := []statecheck.StateCheck{
+		statecheck.ExpectIdentityValueMatchesState("gorules_environment.test", tfjsonpath.New("project_id")),
+		statecheck.ExpectIdentityValueMatchesState("gorules_environment.test", tfjsonpath.New("id")),
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentConfig(srv, "identity", `name = "staging"
+  type = "brms"`),
+				ConfigStateChecks: checks,
+			},
+			// renames keep the identity
+			{
+				Config: testAccEnvironmentConfig(srv, "identity", `name = "production"
+  key  = "production"
+  type = "brms"`),
+				ConfigStateChecks: checks,
+			},
+			{
+				ResourceName:    "gorules_environment.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func testAccProjectChildImportID(name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("%s not found in state", name)
+		}
+		return rs.Primary.Attributes["project_id"] + "/" + rs.Primary.ID, nil
+	}
+}
+
+func TestEnvItemUnmarshalJSON(t *testing.T) {
+	testGolden(t, "environments", func(raw []byte) (any, error) {
+		var items []envItem
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		// only the normalized fields matter here
+		out := make([]map[string]any, len(items))
+		for i, it := range items {
+			out[i] = map[string]any{"id": it.ID, "key": it.Key, "approvalMode": it.ApprovalMode, "approvalGroups": it.ApprovalGroups}
+		}
+		return out, nil
+	})
+}
+
+func FuzzEnvItemUnmarshalJSON(f *testing.F) {
+	f.Add([]byte(`{"id":"e1","approvalGroups":["g1"]}`))
+	f.Add([]byte(`{"id":"e1","approvalGroups":[{"id":"g1","name":"approvers"}]}`))
+	f.Add([]byte(`{"id":"e1","approvalGroups":[1]}`))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		var it envItem
+		if err := json.Unmarshal(raw, &it); err != nil {
+			return
+		}
+		if it.ApprovalGroups == nil {
+			t.Fatalf("nil approval groups for %q", raw)
+		}
+		for _, id := range it.ApprovalGroups {
+			if id == "" {
+				t.Fatalf("empty group id accepted from %q", raw)
+			}
+		}
+	})
+}
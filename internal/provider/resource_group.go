@@ -8,7 +8,9 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -28,6 +30,7 @@ type groupModel struct {
 	Name        types.String   `tfsdk:"name"`
 	Description types.String   `tfsdk:"description"`
 	Permissions []types.String `tfsdk:"permissions"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewGroupResource() resource.Resource { return &groupResource{} }
@@ -36,6 +39,15 @@ func (r *groupResource) Metadata(_ context.Context, _ resource.MetadataRequest,
 	resp.TypeName = "gorules_group"
 }
 
+func (r *groupResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = projectChildIdentitySchema("group")
+}
+
+// ImportState accepts "<project_id>/<group_id>" or an identity {project_id, id}
+func (r *groupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateProjectChild(ctx, req, resp)
+}
+
 func (r *groupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -43,8 +55,9 @@ func (r *groupResource) Configure(_ context.Context, req resource.ConfigureReque
 	r.cfg = req.ProviderData.(*Config)
 }
 
-func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *groupResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = rschema.Schema{
+		Version:             groupSchemaVersion,
 		MarkdownDescription: "Manages groups for a project in GoRules.",
 		Attributes: map[string]rschema.Attribute{
 			"id": rschema.StringAttribute{
@@ -56,7 +69,10 @@ func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			},
 			"project_id": rschema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "Parent project ID.",
+				MarkdownDescription: "Parent project ID. Changing it replaces the group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"name": rschema.StringAttribute{
 				Required:            true,
@@ -72,6 +88,12 @@ func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Required:            true,
 				MarkdownDescription: "Group permissions.",
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -92,6 +114,15 @@ func (r *groupResource) normalizePerms(in []string) []string {
 	return cp
 }
 
+// permissionsState is how Read stores permissions: sorted, unless prior
+// already holds the same set in another order
+func (r *groupResource) permissionsState(in []string, prior []types.String) []types.String {
+	if in == nil {
+		in = []string{}
+	}
+	return ToTFStringListKeepOrder(r.normalizePerms(in), prior)
+}
+
 // Paginated list with real schema {results, paginate}
 func (r *groupResource) listAllGroups(ctx context.Context, projectID string) ([]groupItem, int, []byte, error) {
 	perPage := 200
@@ -101,20 +132,19 @@ func (r *groupResource) listAllGroups(ctx context.Context, projectID string) ([]
 	for {
 		url := fmt.Sprintf("%s/api/projects/%s/groups?perPage=%d&page=%d", r.cfg.BaseURL, projectID, perPage, page)
 		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		req.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 
-		res, err := clientNoRedirect(r.cfg.HTTP).Do(req)
+		res, err := apiClient(r.cfg.HTTP).Do(req)
 		if err != nil {
 			return nil, 0, nil, err
 		}
 		raw, _ := io.ReadAll(res.Body)
 		res.Body.Close()
 
-		if res.StatusCode >= 300 {
-			return nil, res.StatusCode, raw, fmt.Errorf("status=%d", res.StatusCode)
+		if err := checkResponse(res, raw); err != nil {
+			return nil, res.StatusCode, raw, err
 		}
 
-		var gl groupListResponse // definido en http_utils.go
+		var gl groupListResponse // defined in http_utils.go
 		if err := json.Unmarshal(raw, &gl); err != nil {
 			return nil, res.StatusCode, raw, err
 		}
@@ -139,6 +169,31 @@ func (r *groupResource) listAllGroups(ctx context.Context, projectID string) ([]
 	}
 }
 
+func (r *groupResource) findGroup(ctx context.Context, projectID, id string) (*groupItem, error) {
+	items, _, _, err := r.listAllGroups(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].ID == id {
+			return &items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// waitListed waits until the listing shows want (see waitForListing)
+func (r *groupResource) waitListed(ctx context.Context, projectID string, want groupItem) error {
+	return waitForListing(ctx, "group "+want.ID, func(ctx context.Context) (bool, error) {
+		found, err := r.findGroup(ctx, projectID, want.ID)
+		if err != nil || found == nil {
+			return false, err
+		}
+		return found.Name == want.Name &&
+			strings.Join(r.normalizePerms(found.Permissions), ",") == strings.Join(want.Permissions, ","), nil
+	})
+}
+
 // -----------------------------------------------------------------------------
 // Create
 // -----------------------------------------------------------------------------
@@ -155,6 +210,14 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	var descPtr *string
 	if !plan.Description.IsNull() && !plan.Description.IsUnknown() {
 		d := plan.Description.ValueString()
@@ -178,23 +241,21 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 	b, _ := json.Marshal(body)
 	url := fmt.Sprintf("%s/api/projects/%s/groups", r.cfg.BaseURL, plan.ProjectID.ValueString())
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	res, err := clientNoRedirect(r.cfg.HTTP).Do(httpReq)
+	res, err := apiClient(r.cfg.HTTP).Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating Group", err.Error())
+		addAPIError(&resp.Diagnostics, "Create Group", err, nil)
 		return
 	}
 	defer res.Body.Close()
 	raw, _ := io.ReadAll(res.Body)
-	if res.StatusCode >= 300 {
-		resp.Diagnostics.AddError("Create Group falló",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	if err := checkResponse(res, raw); err != nil {
+		addAPIError(&resp.Diagnostics, "Create Group", err, groupFields)
 		return
 	}
 
-	var created groupItem // definido en http_utils.go
+	var created groupItem // defined in http_utils.go
 	if err := json.Unmarshal(raw, &created); err != nil {
 		resp.Diagnostics.AddError("Error parsing Create Group response", err.Error())
 		return
@@ -208,6 +269,7 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 		ID:        types.StringValue(created.ID),
 		ProjectID: types.StringValue(plan.ProjectID.ValueString()),
 		Name:      types.StringValue(created.Name),
+		Timeouts:  plan.Timeouts,
 	}
 	if created.Description != nil {
 		state.Description = types.StringValue(*created.Description)
@@ -216,9 +278,14 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 	} else {
 		state.Description = types.StringValue("")
 	}
-	state.Permissions = ToTFStringList(created.Permissions)
+	state.Permissions = ToTFStringListKeepOrder(created.Permissions, plan.Permissions)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectChildIdentityModel{ProjectID: state.ProjectID, ID: state.ID})...)
+	if err := r.waitListed(ctx, plan.ProjectID.ValueString(), created); err != nil {
+		resp.Diagnostics.AddError("Group not listed after create",
+			err.Error()+"\n\nThe group was created and is kept in state as tainted; the next apply replaces it.")
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -235,38 +302,35 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// identity comes from state; set it first so it survives the early returns below
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectChildIdentityModel{ProjectID: state.ProjectID, ID: state.ID})...)
 
-	items, code, raw, err := r.listAllGroups(ctx, state.ProjectID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Get Group falló", fmt.Sprintf("status=%d body=%s", code, string(raw)))
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	var found *groupItem
-	for i := range items {
-		if items[i].ID == state.ID.ValueString() {
-			found = &items[i]
-			break
-		}
+	found, err := r.findGroup(ctx, state.ProjectID.ValueString(), state.ID.ValueString())
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "Read Group", err, nil)
+		return
 	}
+	// Create/Update wait for the listing, so a miss means it was deleted
 	if found == nil {
-		resp.Diagnostics.AddWarning("Get Group no encontrado (se conserva estado)",
-			"No se halló el group en la lista paginada; se conserva el estado para evitar recreación por error.")
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
-	if found.Permissions == nil {
-		found.Permissions = []string{}
-	}
-	found.Permissions = r.normalizePerms(found.Permissions)
-
 	state.Name = types.StringValue(found.Name)
 	if found.Description != nil {
 		state.Description = types.StringValue(*found.Description)
 	} else {
 		state.Description = types.StringValue("")
 	}
-	state.Permissions = ToTFStringList(found.Permissions)
+	state.Permissions = r.permissionsState(found.Permissions, state.Permissions)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -287,6 +351,14 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var descPtr *string
 	if !plan.Description.IsNull() && !plan.Description.IsUnknown() {
 		d := plan.Description.ValueString()
@@ -309,19 +381,17 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 	b, _ := json.Marshal(body)
 	url := fmt.Sprintf("%s/api/projects/%s/groups/%s", r.cfg.BaseURL, plan.ProjectID.ValueString(), plan.ID.ValueString())
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(b))
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	res, err := clientNoRedirect(r.cfg.HTTP).Do(httpReq)
+	res, err := apiClient(r.cfg.HTTP).Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating Group", err.Error())
+		addAPIError(&resp.Diagnostics, "Update Group", err, nil)
 		return
 	}
 	defer res.Body.Close()
 	raw, _ := io.ReadAll(res.Body)
-	if res.StatusCode >= 300 {
-		resp.Diagnostics.AddError("Update Group falló",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	if err := checkResponse(res, raw); err != nil {
+		addAPIError(&resp.Diagnostics, "Update Group", err, groupFields)
 		return
 	}
 
@@ -339,6 +409,7 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		ID:        types.StringValue(updated.ID),
 		ProjectID: types.StringValue(plan.ProjectID.ValueString()),
 		Name:      types.StringValue(updated.Name),
+		Timeouts:  plan.Timeouts,
 	}
 	if updated.Description != nil {
 		state.Description = types.StringValue(*updated.Description)
@@ -347,9 +418,13 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 	} else {
 		state.Description = types.StringValue("")
 	}
-	state.Permissions = ToTFStringList(updated.Permissions)
+	state.Permissions = ToTFStringListKeepOrder(updated.Permissions, plan.Permissions)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectChildIdentityModel{ProjectID: state.ProjectID, ID: state.ID})...)
+	if err := r.waitListed(ctx, plan.ProjectID.ValueString(), updated); err != nil {
+		resp.Diagnostics.AddError("Group update not listed", err.Error())
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -367,20 +442,26 @@ func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	url := fmt.Sprintf("%s/api/projects/%s/groups/%s", r.cfg.BaseURL, state.ProjectID.ValueString(), state.ID.ValueString())
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 
-	res, err := clientNoRedirect(r.cfg.HTTP).Do(httpReq)
+	res, err := apiClient(r.cfg.HTTP).Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error deleting Group", err.Error())
+		addAPIError(&resp.Diagnostics, "Delete Group", err, nil)
 		return
 	}
 	defer res.Body.Close()
-	if res.StatusCode >= 300 && res.StatusCode != http.StatusNotFound {
-		raw, _ := io.ReadAll(res.Body)
-		resp.Diagnostics.AddWarning("Delete Group no confirmado",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	raw, _ := io.ReadAll(res.Body)
+	if err := checkResponse(res, raw); err != nil && !isNotFound(err) {
+		addAPIWarning(&resp.Diagnostics, "Delete Group", err)
 	}
 	resp.State.RemoveResource(ctx)
 }
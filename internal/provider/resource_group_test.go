@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+func testAccGroupConfig(srv *mockserver.Server, projectKey, body string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "gorules_project" "test" {
+  name = "Group Tests"
+  key  = %q
+}
+
+resource "gorules_group" "test" {
+  project_id = gorules_project.test.id
+  %s
+}
+`, projectKey, body)
+}
+
+func TestAccGroup_basic(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// unsorted on purpose: the API answers sorted permissions
+				Config: testAccGroupConfig(srv, "group-tests", `name        = "editors"
+  permissions = ["write", "read"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gorules_group.test", "id"),
+					resource.TestCheckResourceAttr("gorules_group.test", "description", ""),
+					resource.TestCheckResourceAttr("gorules_group.test", "permissions.0", "write"),
+					resource.TestCheckResourceAttr("gorules_group.test", "permissions.1", "read"),
+				),
+			},
+			{
+				Config: testAccGroupConfig(srv, "group-tests", `name        = "publishers"
+  description = "Can publish"
+  permissions = ["write", "read", "publish"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_group.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_group.test", "name", "publishers"),
+					resource.TestCheckResourceAttr("gorules_group.test", "description", "Can publish"),
+					resource.TestCheckResourceAttr("gorules_group.test", "permissions.#", "3"),
+				),
+			},
+			{
+				ResourceName:            "gorules_group.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccProjectChildImportID("gorules_group.test"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"permissions"},
+			},
+		},
+	})
+}
+
+func TestAccGroup_emptyAndNullPermissions(t *testing.T) {
+	// the API answers "permissions": null for groups without permissions
+	srv := testAccServer(t, mockserver.Options{NullPermissions: true, GroupsPageSize: 1})
+	srv.AddGroup(mockserver.Group{ProjectID: "unrelated", Name: "noise"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupConfig(srv, "null-perms", `name        = "viewers"
+  permissions = []`),
+				Check: resource.TestCheckResourceAttr("gorules_group.test", "permissions.#", "0"),
+			},
+			{
+				Config: testAccGroupConfig(srv, "null-perms", `name        = "viewers"
+  permissions = []`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccGroup_driftOutOfBand(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccGroupConfig(srv, "group-drift", `name        = "editors"
+  permissions = ["read"]`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					for _, p := range srv.Projects() {
+						for _, g := range srv.Groups(p.ID) {
+							srv.UpdateGroup(p.ID, g.ID, func(g *mockserver.Group) { g.Permissions = []string{"read", "admin"} })
+						}
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_group.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("gorules_group.test", "permissions.#", "1"),
+			},
+		},
+	})
+}
+
+// a group missing from the listing was deleted and is planned again
+func TestAccGroup_deletedOutOfBand(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccGroupConfig(srv, "group-gone", `name        = "editors"
+  permissions = ["read"]`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{Config: config},
+			{
+				PreConfig: func() {
+					for _, p := range srv.Projects() {
+						for _, g := range srv.Groups(p.ID) {
+							srv.RemoveGroup(p.ID, g.ID)
+						}
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_group.test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccGroup_identity(t *testing.T) {
+	srv := testAccServer(t)
+	checks := []statecheck.StateCheck{
+		statecheck.ExpectIdentityValueMatchesState("gorules_group.test", tfjsonpath.New("project_id")),
+		statecheck.ExpectIdentityValueMatchesState("gorules_group.test", tfjsonpath.New("id")),
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupConfig(srv, "identity", `name        = "editors"
+  permissions = []`),
+				ConfigStateChecks: checks,
+			},
+			// renames keep the identity
+			{
+				Config: testAccGroupConfig(srv, "identity", `name        = "reviewers"
+  permissions = []`),
+				ConfigStateChecks: checks,
+			},
+			{
+				ResourceName:    "gorules_group.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func TestAccGroup_moveState(t *testing.T) {
+	srv := testAccServer(t)
+	// the same provider under another name stands in for a community GoRules provider
+	factories := map[string]func() (tfprotov6.ProviderServer, error){
+		"gorules":    testAccProtoV6ProviderFactories["gorules"],
+		"gorulesold": testAccProtoV6ProviderFactories["gorules"],
+	}
+	config := func(group string) string {
+		return testAccGroupConfig(srv, "move-state", `name        = "editors"
+  permissions = ["read"]`) + fmt.Sprintf(`
+provider "gorulesold" {
+  base_url = %q
+  token    = %q
+}
+%s`, srv.URL, srv.Token, group)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: factories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+resource "gorules_group" "old" {
+  provider    = gorulesold
+  project_id  = gorules_project.test.id
+  name        = "approvers"
+  permissions = ["deploy"]
+}
+`),
+			},
+			{
+				Config: config(`
+resource "gorules_group" "new" {
+  project_id  = gorules_project.test.id
+  name        = "approvers"
+  permissions = ["deploy"]
+}
+
+moved {
+  from = gorules_group.old
+  to   = gorules_group.new
+}
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_group.new", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("gorules_group.new", "permissions.0", "deploy"),
+			},
+		},
+	})
+}
@@ -9,13 +9,17 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // -----------------------------------------------------------------------------
@@ -25,11 +29,13 @@ import (
 type projectResource struct{ cfg *Config }
 
 type projectModel struct {
-	ID             types.String `tfsdk:"id"`               // UUID returned by the API
-	Name           types.String `tfsdk:"name"`             // required
-	Key            types.String `tfsdk:"key"`              // required: ^[a-z0-9]{2,}(-[a-z0-9]+)*$
-	Protected      types.Bool   `tfsdk:"protected"`        // optional+computed
-	CopyContentRef types.String `tfsdk:"copy_content_ref"` // optional: UUID to copy
+	ID             types.String   `tfsdk:"id"`               // UUID returned by the API
+	Name           types.String   `tfsdk:"name"`             // required
+	Key            types.String   `tfsdk:"key"`              // optional+computed: ^[a-z0-9]{2,}(-[a-z0-9]+)*$, derived from name when omitted
+	Protected      types.Bool     `tfsdk:"protected"`        // optional+computed
+	CopyContentRef types.String   `tfsdk:"copy_content_ref"` // optional: UUID to copy
+	ForceDestroy   types.Bool     `tfsdk:"force_destroy"`    // Terraform-only: delete children and protected projects on destroy
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Payload for creating/updating
@@ -53,17 +59,66 @@ type projectEnvelope struct {
 	Project projectFlat `json:"project"`
 }
 
-// Flexible parser
+// parseProjectJSON accepts the flat and the {"project": {...}} shapes. The
+// shape is decided by the presence of the "project" member; anything else
+// (non-objects, wrong member types, no id/name/key) is an error.
 func parseProjectJSON(raw []byte) (projectFlat, error) {
-	var env projectEnvelope
-	if err := json.Unmarshal(raw, &env); err == nil && (env.Project.ID != "" || env.Project.Name != "" || env.Project.Key != "") {
-		return env.Project, nil
+	if kind := jsonKind(raw); kind != "object" {
+		return projectFlat{}, fmt.Errorf("project response: expected object, got %s", kind)
 	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return projectFlat{}, fmt.Errorf("project response: %w", err)
+	}
+	if inner, ok := members["project"]; ok {
+		if kind := jsonKind(inner); kind != "object" {
+			return projectFlat{}, fmt.Errorf("project response: expected \"project\" object, got %s", kind)
+		}
+		raw = inner
+	}
+
 	var pf projectFlat
-	if err := json.Unmarshal(raw, &pf); err == nil && (pf.ID != "" || pf.Name != "" || pf.Key != "") {
-		return pf, nil
+	if err := json.Unmarshal(raw, &pf); err != nil {
+		return projectFlat{}, fmt.Errorf("project response: %w", err)
+	}
+	if pf.ID == "" && pf.Name == "" && pf.Key == "" {
+		return projectFlat{}, fmt.Errorf("project response: no id, name or key")
 	}
-	return projectFlat{}, fmt.Errorf("could not parse project response")
+	return pf, nil
+}
+
+// listProjects returns all projects visible to the token. The list is a JSON
+// array, or {"results": [...]} on servers that paginate it.
+func listProjects(ctx context.Context, cfg *Config) ([]projectFlat, error) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, cfg.BaseURL+"/api/projects", nil)
+	req.Header.Set("Accept", "application/json")
+	res, err := apiClient(cfg.HTTP).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	if err := checkResponse(res, raw); err != nil {
+		return nil, err
+	}
+
+	if jsonKind(raw) == "object" {
+		var page struct {
+			Results json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("project list: %w", err)
+		}
+		raw = page.Results
+	}
+	if kind := jsonKind(raw); kind != "array" {
+		return nil, fmt.Errorf("project list: expected array, got %s", kind)
+	}
+	var out []projectFlat
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("project list: %w", err)
+	}
+	return out, nil
 }
 
 // Helper: use server if provided; otherwise keep plan; if both empty → null
@@ -77,14 +132,53 @@ func firstNonEmptyStringTF(server string, plan types.String) types.String {
 	return types.StringNull()
 }
 
+// keyFromNameModifier plans `key` from `name` when key is not configured and
+// the project is being created; existing projects keep their key
+type keyFromNameModifier struct{}
+
+func (m keyFromNameModifier) Description(_ context.Context) string {
+	return "Derives the key from name on create when key is not set; existing projects keep their key."
+}
+
+func (m keyFromNameModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m keyFromNameModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	// renamed or imported: keep the key instead of planning a new one
+	if !req.StateValue.IsNull() && !req.StateValue.IsUnknown() {
+		resp.PlanValue = req.StateValue
+		return
+	}
+	var name types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if name.IsUnknown() {
+		resp.PlanValue = types.StringUnknown()
+		return
+	}
+	key, err := projectKeyFromName(name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Cannot derive project key", err.Error()+". Set key explicitly.")
+		return
+	}
+	resp.PlanValue = types.StringValue(key)
+}
+
 func NewProjectResource() resource.Resource { return &projectResource{} }
 
 func (r *projectResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = "gorules_project"
 }
 
-func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *projectResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = rschema.Schema{
+		Version:             projectSchemaVersion,
 		MarkdownDescription: "Creates and manages projects in GoRules.",
 		Attributes: map[string]rschema.Attribute{
 			"id": rschema.StringAttribute{
@@ -99,8 +193,12 @@ func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				MarkdownDescription: "Project name.",
 			},
 			"key": rschema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Unique key (regex: `^[a-z0-9]{2,}(-[a-z0-9]+)*$`).",
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Unique key (regex: `^[a-z0-9]{2,}(-[a-z0-9]+)*$`). If omitted, derived from `name` when the project is created (see `provider::gorules::key_from_name`); renaming the project keeps the key.",
+				PlanModifiers: []planmodifier.String{
+					keyFromNameModifier{},
+				},
 			},
 			"protected": rschema.BoolAttribute{
 				Optional:            true,
@@ -112,12 +210,66 @@ func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 			},
 			"copy_content_ref": rschema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "UUID of project to copy (content clone).",
+				MarkdownDescription: "UUID of project to copy (content clone). Only used on create: later changes, and values set after an import, are only recorded in state.",
 			},
+			"force_destroy": rschema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "On destroy, delete the project's deployments, documents, environments and groups first, and allow destroying it while `protected` is true (it is unprotected once its contents are deleted). Only stored in state: apply it before destroying. Default: `false`.",
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+func (r *projectResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = projectIdentitySchema()
+}
+
+// ImportState accepts a project ID or key, or an identity {id}
+func (r *projectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" {
+		resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
+	} else {
+		id, err := r.resolveImportID(ctx, req.ID)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Import Project", err, nil)
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_destroy"), false)...)
+}
+
+// resolveImportID maps an import ID that is a project key to the project ID.
+// IDs win over keys; unknown values are passed through and Read reports them.
+func (r *projectResource) resolveImportID(ctx context.Context, idOrKey string) (string, error) {
+	if r.cfg == nil {
+		return idOrKey, nil
+	}
+	projects, err := listProjects(ctx, r.cfg)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range projects {
+		if p.ID == idOrKey {
+			return idOrKey, nil
+		}
+	}
+	for _, p := range projects {
+		if p.Key == idOrKey {
+			return p.ID, nil
+		}
+	}
+	return idOrKey, nil
+}
+
 func (r *projectResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -136,6 +288,14 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	body := createProjectRequest{
 		Name: plan.Name.ValueString(),
 		Key:  plan.Key.ValueString(),
@@ -151,41 +311,42 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 	b, _ := json.Marshal(body)
 	url := fmt.Sprintf("%s/api/projects", r.cfg.BaseURL)
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
 
-	client := clientNoRedirect(r.cfg.HTTP)
+	client := apiClient(r.cfg.HTTP)
 	res, err := client.Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating project", err.Error())
+		addAPIError(&resp.Diagnostics, "Create Project", err, nil)
 		return
 	}
 	defer res.Body.Close()
 	raw, _ := io.ReadAll(res.Body)
-	if res.StatusCode >= 300 && res.StatusCode < 400 {
-		resp.Diagnostics.AddWarning("Create Project returned redirect",
-			fmt.Sprintf("status=%d location=%s", res.StatusCode, res.Header.Get("Location")))
-	}
-	if res.StatusCode >= 300 {
-		resp.Diagnostics.AddAttributeError(path.Root("name"), "Create Project failed",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	if err := checkResponse(res, raw); err != nil {
+		addAPIError(&resp.Diagnostics, "Create Project", err, projectFields)
 		return
 	}
 
-	pf, _ := parseProjectJSON(raw)
+	pf, parseErr := parseProjectJSON(raw)
 	if pf.ID == "" {
 		if loc := res.Header.Get("Location"); loc != "" {
 			parts := strings.Split(strings.TrimRight(loc, "/"), "/")
 			pf.ID = parts[len(parts)-1]
 		}
 	}
+	if pf.ID == "" {
+		detail := "The response has no project id and no Location header."
+		if parseErr != nil {
+			detail = parseErr.Error()
+		}
+		resp.Diagnostics.AddError("Error parsing Create Project response", detail)
+		return
+	}
 
 	// Hydrate with GET (tolerant)
 	if pf.ID != "" {
 		getURL := fmt.Sprintf("%s/api/projects/%s", r.cfg.BaseURL, pf.ID)
 		getReq, _ := http.NewRequestWithContext(ctx, http.MethodGet, getURL, nil)
-		getReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 		getReq.Header.Set("Accept", "application/json")
 
 		if getRes, getErr := client.Do(getReq); getErr == nil && getRes != nil {
@@ -218,8 +379,11 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		Key:            keyTF,
 		Protected:      protectedTF,
 		CopyContentRef: plan.CopyContentRef,
+		ForceDestroy:   plan.ForceDestroy,
+		Timeouts:       plan.Timeouts,
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectIdentityModel{ID: state.ID})...)
 }
 
 func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -232,45 +396,42 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// identity comes from state; set it first so it survives the early returns below
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectIdentityModel{ID: state.ID})...)
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
 	url := fmt.Sprintf("%s/api/projects/%s", r.cfg.BaseURL, state.ID.ValueString())
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 	httpReq.Header.Set("Accept", "application/json")
 
-	client := clientNoRedirect(r.cfg.HTTP)
+	client := apiClient(r.cfg.HTTP)
 	res, err := client.Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading project", err.Error())
+		addAPIError(&resp.Diagnostics, "Read Project", err, nil)
 		return
 	}
 	defer res.Body.Close()
-	if res.StatusCode == http.StatusNotFound {
-		resp.State.RemoveResource(ctx)
-		return
-	}
-	if res.StatusCode >= 300 && res.StatusCode < 400 {
-		resp.Diagnostics.AddWarning("Get Project returned redirect",
-			fmt.Sprintf("status=%d location=%s", res.StatusCode, res.Header.Get("Location")))
-		return
-	}
-	if res.StatusCode >= 500 {
-		raw, _ := io.ReadAll(res.Body)
-		resp.Diagnostics.AddWarning("Get Project failed (5xx, preserving state)",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
-		return
-	}
-	if res.StatusCode >= 400 {
-		raw, _ := io.ReadAll(res.Body)
-		resp.Diagnostics.AddWarning("Get Project returned 4xx (preserving state)",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	raw, _ := io.ReadAll(res.Body)
+	if err := checkResponse(res, raw); err != nil {
+		if isNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		// other failures keep the current state (reported as a warning)
+		addAPIWarning(&resp.Diagnostics, "Read Project (state kept)", err)
 		return
 	}
 
-	raw, _ := io.ReadAll(res.Body)
 	pf, err := parseProjectJSON(raw)
 	if err != nil {
-		resp.Diagnostics.AddWarning("Project parsing failed (preserving state)", err.Error())
+		resp.Diagnostics.AddWarning("Read Project: unexpected response (state kept)", err.Error())
 		return
 	}
 
@@ -295,6 +456,25 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// only copy_content_ref, force_destroy or timeouts changed: they are not
+	// sent to the BRMS, so just record them
+	if plan.Name.Equal(state.Name) && plan.Key.Equal(state.Key) && plan.Protected.Equal(state.Protected) {
+		state.CopyContentRef = plan.CopyContentRef
+		state.ForceDestroy = plan.ForceDestroy
+		state.Timeouts = plan.Timeouts
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, projectIdentityModel{ID: state.ID})...)
+		return
+	}
+
 	var protectedVal *bool
 	if !plan.Protected.IsNull() && !plan.Protected.IsUnknown() {
 		v := plan.Protected.ValueBool()
@@ -317,35 +497,28 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	url := fmt.Sprintf("%s/api/projects/%s", r.cfg.BaseURL, state.ID.ValueString())
 	makeReq := func() *http.Request {
 		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(b))
-		req.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json")
 		return req
 	}
 
-	client := clientNoRedirect(r.cfg.HTTP)
+	client := apiClient(r.cfg.HTTP)
 
 	httpReq := makeReq()
 	res, err := client.Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating project", err.Error())
+		addAPIError(&resp.Diagnostics, "Update Project", err, nil)
 		return
 	}
 	raw, _ := io.ReadAll(res.Body)
 	res.Body.Close()
 
-	if res.StatusCode >= 300 && res.StatusCode < 400 {
-		resp.Diagnostics.AddError("Update Project returned redirect",
-			fmt.Sprintf("status=%d location=%s", res.StatusCode, res.Header.Get("Location")))
-		return
-	}
-
 	if res.StatusCode == http.StatusUnauthorized {
 		// single soft retry
 		httpReq = makeReq()
 		res2, err2 := client.Do(httpReq)
 		if err2 != nil {
-			resp.Diagnostics.AddError("Error updating project (retry)", err2.Error())
+			addAPIError(&resp.Diagnostics, "Update Project (retry)", err2, nil)
 			return
 		}
 		raw, _ = io.ReadAll(res2.Body)
@@ -353,16 +526,27 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 		res = res2
 	}
 
-	if res.StatusCode >= 300 {
-		resp.Diagnostics.AddError("Update Project failed",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+	if err := checkResponse(res, raw); err != nil {
+		addAPIError(&resp.Diagnostics, "Update Project", err, projectFields)
 		return
 	}
 
-	pf, _ := parseProjectJSON(raw)
+	// some deployments answer 204 on update; only a body that is present must parse
+	var pf projectFlat
+	if len(bytes.TrimSpace(raw)) > 0 {
+		parsed, err := parseProjectJSON(raw)
+		if err != nil {
+			resp.Diagnostics.AddError("Error parsing Update Project response", err.Error())
+			return
+		}
+		pf = parsed
+	}
 
 	state.Name = firstNonEmptyStringTF(pf.Name, plan.Name)
 	state.Key = firstNonEmptyStringTF(pf.Key, plan.Key)
+	state.CopyContentRef = plan.CopyContentRef
+	state.ForceDestroy = plan.ForceDestroy
+	state.Timeouts = plan.Timeouts
 	if pf.Protected != nil {
 		state.Protected = types.BoolValue(*pf.Protected)
 	} else if protectedVal != nil {
@@ -372,6 +556,7 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, projectIdentityModel{ID: state.ID})...)
 }
 
 func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -385,22 +570,82 @@ func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	protected := state.Protected.ValueBool()
+	if protected && !state.ForceDestroy.ValueBool() {
+		resp.Diagnostics.AddError("Project is protected",
+			fmt.Sprintf("Project %s has protected = true and cannot be destroyed. Set protected = false, or force_destroy = true, and apply before destroying it.", state.ID.ValueString()))
+		return
+	}
+
+	// children go first: if that fails the project is still protected, as in state
+	if state.ForceDestroy.ValueBool() && !r.deleteChildren(ctx, state.ID.ValueString(), &resp.Diagnostics) {
+		return
+	}
+	if protected {
+		tflog.Info(ctx, "Unprotecting project before deletion", map[string]interface{}{"project_id": state.ID.ValueString()})
+		if err := r.setProtected(ctx, state, false); err != nil {
+			addAPIError(&resp.Diagnostics, "Unprotect Project", err, nil)
+			return
+		}
+	}
+
 	url := fmt.Sprintf("%s/api/projects/%s", r.cfg.BaseURL, state.ID.ValueString())
 	httpReq, _ := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
-	httpReq.Header.Set("Authorization", "Bearer "+r.cfg.Token)
 	httpReq.Header.Set("Accept", "application/json")
 
-	client := clientNoRedirect(r.cfg.HTTP)
+	client := apiClient(r.cfg.HTTP)
 	res, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error deleting project", err.Error())
-		return
-	}
-	defer res.Body.Close()
-	if res.StatusCode >= 300 && res.StatusCode != http.StatusNotFound {
+	if err == nil {
 		raw, _ := io.ReadAll(res.Body)
-		resp.Diagnostics.AddWarning("Delete Project not confirmed",
-			fmt.Sprintf("status=%d body=%s", res.StatusCode, string(raw)))
+		res.Body.Close()
+		err = checkResponse(res, raw)
+	}
+	// a refused delete keeps the project in state; 404 means it is already gone
+	if err != nil && !isNotFound(err) {
+		addAPIError(&resp.Diagnostics, "Delete Project", err, nil)
+		if protected {
+			r.reprotect(ctx, state, &resp.Diagnostics)
+		}
+		return
 	}
 	resp.State.RemoveResource(ctx)
 }
+
+// reprotect restores protected = true after a failed delete, so the project
+// matches its state again
+func (r *projectResource) reprotect(ctx context.Context, state projectModel, diags *diag.Diagnostics) {
+	// the delete may have failed because the delete timeout expired; the
+	// request is still bounded by the provider timeout
+	if err := r.setProtected(context.WithoutCancel(ctx), state, true); err != nil {
+		addAPIWarning(diags, "Re-protect Project", err)
+	}
+}
+
+// setProtected sets the protected flag, e.g. so the project can be deleted
+func (r *projectResource) setProtected(ctx context.Context, state projectModel, protected bool) error {
+	b, _ := json.Marshal(createProjectRequest{
+		Name:      state.Name.ValueString(),
+		Key:       state.Key.ValueString(),
+		Protected: &protected,
+	})
+	url := fmt.Sprintf("%s/api/projects/%s", r.cfg.BaseURL, state.ID.ValueString())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := apiClient(r.cfg.HTTP).Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(res.Body)
+	return checkResponse(res, raw)
+}
@@ -0,0 +1,600 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+func testAccProjectConfig(srv *mockserver.Server, name string, extra string) string {
+	return testAccProviderConfig(srv) + fmt.Sprintf(`
+resource "gorules_project" "test" {
+  name = %q
+  %s
+}
+`, name, extra)
+}
+
+func TestAccProject_basic(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Pricing Rules", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gorules_project.test", "id"),
+					resource.TestCheckResourceAttr("gorules_project.test", "key", "pricing-rules"),
+					resource.TestCheckResourceAttr("gorules_project.test", "protected", "false"),
+				),
+			},
+			// rename with a pinned key: updated in place
+			{
+				Config: testAccProjectConfig(srv, "Pricing", `key = "pricing-rules"
+  protected = true`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_project.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_project.test", "name", "Pricing"),
+					resource.TestCheckResourceAttr("gorules_project.test", "key", "pricing-rules"),
+					resource.TestCheckResourceAttr("gorules_project.test", "protected", "true"),
+				),
+			},
+			{
+				ResourceName:      "gorules_project.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// unprotect so the destroy at the end of the test succeeds
+			{
+				Config: testAccProjectConfig(srv, "Pricing", `key = "pricing-rules"
+  protected = false`),
+			},
+		},
+	})
+}
+
+func TestAccProject_flatResponses(t *testing.T) {
+	srv := testAccServer(t, mockserver.Options{FlatProjects: true})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Flat", `key = "flat"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gorules_project.test", "id"),
+					resource.TestCheckResourceAttr("gorules_project.test", "key", "flat"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProject_copyContentRef(t *testing.T) {
+	srv := testAccServer(t)
+	src := srv.AddProject(mockserver.Project{Name: "Template", Key: "template"})
+	other := srv.AddProject(mockserver.Project{Name: "Other Template", Key: "other-template"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Clone", fmt.Sprintf("copy_content_ref = %q", src.ID)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_project.test", "copy_content_ref", src.ID),
+					func(*terraform.State) error {
+						for _, r := range srv.Requests() {
+							if r.Method == "POST" && r.Path == "/api/projects" && strings.Contains(string(r.Body), src.ID) {
+								return nil
+							}
+						}
+						return fmt.Errorf("copyContentRef not sent on create")
+					},
+				),
+			},
+			// only used on create: changing or removing it is recorded in
+			// state without replacing the project or calling the BRMS
+			{
+				PreConfig: srv.ResetRequests,
+				Config:    testAccProjectConfig(srv, "Clone", fmt.Sprintf("copy_content_ref = %q", other.ID)),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_project.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_project.test", "copy_content_ref", other.ID),
+					testAccCheckNoProjectWrites(srv),
+				),
+			},
+			{
+				Config: testAccProjectConfig(srv, "Clone", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_project.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("gorules_project.test", "copy_content_ref"),
+					testAccCheckNoProjectWrites(srv),
+				),
+			},
+			// changed together with the name: the PUT still records the new ref
+			{
+				Config: testAccProjectConfig(srv, "Renamed Clone", fmt.Sprintf("copy_content_ref = %q", src.ID)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_project.test", "name", "Renamed Clone"),
+					resource.TestCheckResourceAttr("gorules_project.test", "copy_content_ref", src.ID),
+				),
+			},
+			// imported projects have no copy_content_ref in state
+			{
+				ResourceName:            "gorules_project.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"copy_content_ref"},
+			},
+		},
+	})
+}
+
+func TestAccProject_importWithCopyContentRef(t *testing.T) {
+	srv := testAccServer(t)
+	src := srv.AddProject(mockserver.Project{Name: "Template", Key: "template"})
+	p := srv.AddProject(mockserver.Project{Name: "Clone", Key: "clone"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Clone", fmt.Sprintf(`key              = "clone"
+  copy_content_ref = %q`, src.ID)) + fmt.Sprintf(`
+import {
+  to = gorules_project.test
+  id = %q
+}
+`, p.ID),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_project.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testAccCheckNoProjectWrites(srv),
+			},
+		},
+	})
+}
+
+// testAccCheckNoProjectWrites checks that no project was created, updated or
+// replaced since the requests were last reset
+func testAccCheckNoProjectWrites(srv *mockserver.Server) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, r := range srv.Requests() {
+			if strings.HasPrefix(r.Path, "/api/projects") && (r.Method == "POST" || r.Method == "PUT" || r.Method == "DELETE") {
+				return fmt.Errorf("unexpected %s %s", r.Method, r.Path)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccProject_deletedOutOfBand(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccProjectConfig(srv, "Ephemeral", "")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					for _, p := range srv.Projects() {
+						srv.RemoveProject(p.ID)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_project.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttrSet("gorules_project.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccProject_duplicateKey(t *testing.T) {
+	srv := testAccServer(t)
+	srv.AddProject(mockserver.Project{Name: "Existing", Key: "pricing"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProjectConfig(srv, "Pricing", `key = "pricing"`),
+				ExpectError: regexp.MustCompile(`(?s)Create Project failed: 409 Conflict.*already exists.*terraform import`),
+			},
+		},
+	})
+}
+
+func TestAccProject_protected(t *testing.T) {
+	srv := testAccServer(t)
+	protected := testAccProjectConfig(srv, "Protected", `protected = true`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if n := len(srv.Projects()); n != 0 {
+				return fmt.Errorf("%d projects left after destroy", n)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: protected,
+				Check:  resource.TestCheckResourceAttr("gorules_project.test", "force_destroy", "false"),
+			},
+			{
+				Config:      protected,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Project is protected`),
+			},
+			// force_destroy unprotects the project in the final destroy
+			{
+				Config: testAccProjectConfig(srv, "Protected", `protected = true
+  force_destroy = true`),
+				Check: resource.TestCheckResourceAttr("gorules_project.test", "protected", "true"),
+			},
+		},
+	})
+}
+
+func TestAccProject_deleteFailure(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccProjectConfig(srv, "Delete Failure", "")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{Config: config},
+			// a refused delete is an error and the project stays in state
+			{
+				PreConfig: func() {
+					srv.Inject(mockserver.Fault{Method: "DELETE", PathPrefix: "/api/projects/", Status: 500, Times: 1})
+				},
+				Config:      config,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Delete Project failed: 500`),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccProject_forceDestroy(t *testing.T) {
+	srv := testAccServer(t)
+	var id string
+	// children created outside Terraform
+	seed := func(*terraform.State) error {
+		env := srv.AddEnvironment(mockserver.Environment{ProjectID: id, Name: "Production", Key: "production", Type: "deployment"})
+		srv.AddGroup(mockserver.Group{ProjectID: id, Name: "Approvers"})
+		doc := srv.AddDocument(mockserver.Document{ProjectID: id, Name: "pricing.json", Type: "decision"})
+		srv.AddDeployment(mockserver.Deployment{ProjectID: id, EnvironmentID: env.ID, DocumentID: doc.ID})
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if n := len(srv.Projects()); n != 0 {
+				return fmt.Errorf("%d projects left after destroy", n)
+			}
+			// children are deleted in dependency order, before the project
+			var order []string
+			for _, r := range srv.Requests() {
+				if child, ok := strings.CutPrefix(r.Path, "/api/projects/"+id+"/"); ok && r.Method == "DELETE" {
+					order = append(order, strings.Split(child, "/")[0])
+				}
+			}
+			if got := strings.Join(order, ","); got != "deployments,documents,environments,groups" {
+				return fmt.Errorf("unexpected DELETE order: %s", got)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Children", ""),
+				Check:  resource.ComposeTestCheckFunc(testAccCaptureID("gorules_project.test", &id), seed),
+			},
+			// without force_destroy the server refuses to delete a project with documents
+			{
+				Config:      testAccProjectConfig(srv, "Children", ""),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Delete Project failed: 409 Conflict`),
+			},
+			{
+				Config: testAccProjectConfig(srv, "Children", `force_destroy = true`),
+			},
+		},
+	})
+}
+
+func TestAccProject_forceDestroyFailure(t *testing.T) {
+	srv := testAccServer(t)
+	config := testAccProjectConfig(srv, "Guarded", `protected = true
+  force_destroy = true`)
+	var id, docID string
+	// expectProtected checks, before the next step, that a failed destroy
+	// left the project protected as recorded in state
+	expectProtected := func() {
+		if p, _ := srv.Project(id); !p.Protected {
+			t.Errorf("project %s left unprotected after a failed destroy", id)
+		}
+		srv.ClearFaults()
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(testAccCaptureID("gorules_project.test", &id), func(*terraform.State) error {
+					docID = srv.AddDocument(mockserver.Document{ProjectID: id, Name: "pricing.json", Type: "decision"}).ID
+					return nil
+				}),
+			},
+			// deleting a child fails: the project is never unprotected
+			{
+				PreConfig: func() {
+					srv.ResetRequests()
+					srv.Inject(mockserver.Fault{Method: "DELETE", PathPrefix: "/api/projects/" + id + "/documents", Status: http.StatusInternalServerError})
+				},
+				Config:      config,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`500`),
+			},
+			// deleting the project itself fails: it is protected again
+			{
+				PreConfig: func() {
+					expectProtected()
+					if n := srv.CountRequests("PUT", "/api/projects/"+id); n != 0 {
+						t.Errorf("project updated %d times before its children were deleted", n)
+					}
+					srv.RemoveDocument(id, docID)
+					srv.Inject(mockserver.Fault{Method: "DELETE", PathPrefix: "/api/projects/" + id, Status: http.StatusConflict, Body: `{"message":"project is locked"}`})
+				},
+				Config:      config,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`project is locked`),
+			},
+			{
+				PreConfig: expectProtected,
+				Config:    config,
+				PlanOnly:  true,
+			},
+		},
+	})
+}
+
+func TestAccProject_timeouts(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					srv.Inject(mockserver.Fault{Method: "POST", PathPrefix: "/api/projects", Delay: 5 * time.Second, Times: 1})
+				},
+				Config: testAccProjectConfig(srv, "Slow", `timeouts = {
+    create = "1s"
+  }`),
+				ExpectError: regexp.MustCompile(`(?s)Create Project failed.*timed out`),
+			},
+		},
+	})
+}
+
+// load balancers adding a trailing slash answer 308; the update must go through
+func TestAccProject_redirectedUpdate(t *testing.T) {
+	srv := testAccServer(t)
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Redirects", `key = "redirects"`),
+				Check:  testAccCaptureID("gorules_project.test", &id),
+			},
+			{
+				PreConfig: func() {
+					srv.Inject(mockserver.Fault{Method: "PUT", Status: 308, Location: "/api/projects/" + id + "/", Times: 1})
+				},
+				Config: testAccProjectConfig(srv, "Redirected", `key = "redirects"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gorules_project.test", "name", "Redirected"),
+					func(*terraform.State) error {
+						if n := srv.CountRequests("PUT", "/api/projects/"+id+"/"); n != 1 {
+							return fmt.Errorf("expected the redirected PUT to be followed, got %d", n)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccProject_derivedKey(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Tarificación Europa", ""),
+				Check:  resource.TestCheckResourceAttr("gorules_project.test", "key", "tarificacion-europa"),
+			},
+			// renaming keeps the key derived on create
+			{
+				Config: testAccProjectConfig(srv, "Pricing EU", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_project.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("gorules_project.test", tfjsonpath.New("key"), knownvalue.StringExact("tarificacion-europa")),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("gorules_project.test", "key", "tarificacion-europa"),
+			},
+		},
+	})
+}
+
+func TestAccProject_importKeepsKey(t *testing.T) {
+	srv := testAccServer(t)
+	p := srv.AddProject(mockserver.Project{Name: "Legacy Rules", Key: "legacy"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				// the derived key would be "legacy-rules"
+				Config: testAccProjectConfig(srv, "Legacy Rules", "") + fmt.Sprintf(`
+import {
+  to = gorules_project.test
+  id = %q
+}
+`, p.ID),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gorules_project.test", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("gorules_project.test", "key", "legacy"),
+			},
+		},
+	})
+}
+
+// testAccCaptureID stores the ID of name into dst
+func TestAccProject_importByKey(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Pricing", `key = "pricing"`),
+			},
+			{
+				ResourceName:      "gorules_project.test",
+				ImportState:       true,
+				ImportStateId:     "pricing",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccProject_identity(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectConfig(srv, "Pricing", `key = "pricing"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentityValueMatchesState("gorules_project.test", tfjsonpath.New("id")),
+				},
+			},
+			// renaming name and key keeps the identity
+			{
+				Config: testAccProjectConfig(srv, "Pricing EU", `key = "pricing-eu"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentityValueMatchesState("gorules_project.test", tfjsonpath.New("id")),
+				},
+			},
+			{
+				ResourceName:    "gorules_project.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func testAccCaptureID(name string, dst *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+		*dst = rs.Primary.ID
+		return nil
+	}
+}
+
+func TestParseProjectJSON(t *testing.T) {
+	testGolden(t, "projects", func(raw []byte) (any, error) {
+		return parseProjectJSON(raw)
+	})
+
+	for _, raw := range []string{``, `null`, `"id"`, `{}`, `{"project":"p1"}`, `{"id":"p1"`} {
+		if pf, err := parseProjectJSON([]byte(raw)); err == nil {
+			t.Errorf("parseProjectJSON(%q) = %+v, want error", raw, pf)
+		}
+	}
+}
+
+func FuzzParseProjectJSON(f *testing.F) {
+	testdataSeeds(f, "projects")
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		pf, err := parseProjectJSON(raw)
+		if err != nil {
+			return
+		}
+		if pf.ID == "" && pf.Name == "" && pf.Key == "" {
+			t.Fatalf("accepted a project without id, name or key: %q", raw)
+		}
+		if !json.Valid(raw) {
+			t.Fatalf("accepted invalid JSON: %q", raw)
+		}
+	})
+}
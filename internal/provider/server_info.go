@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// -----------------------------------------------------------------------------
+// Startup check & server information
+// -----------------------------------------------------------------------------
+
+// Features reported by GET /api/version, used for gating
+const (
+	FeatureSimulate     = "simulate"      // POST /api/projects/{id}/simulate
+	FeatureAccessTokens = "access-tokens" // POST/DELETE /api/projects/{id}/access-tokens
+)
+
+// ServerInfo describes the BRMS the provider talks to. It is nil when
+// skip_credentials_validation is set.
+type ServerInfo struct {
+	Version  string          // empty when the server does not report it
+	Features map[string]bool // nil when the server does not report them
+}
+
+// Supports reports whether the server has a feature. Unknown servers are
+// assumed to support everything, so gating never blocks older deployments
+// that simply don't publish their capabilities.
+func (s *ServerInfo) Supports(feature string) bool {
+	if s == nil || s.Features == nil {
+		return true
+	}
+	return s.Features[feature]
+}
+
+type versionResponse struct {
+	Version  string   `json:"version"`
+	Features []string `json:"features"`
+}
+
+// checkServer verifies that base_url is a reachable GoRules BRMS and that the
+// credentials are accepted. GET /api/version is authenticated, so on servers
+// that publish it one call checks both and reads the version; older releases
+// answer 404 there and are checked with the project list instead.
+func checkServer(ctx context.Context, cfg *Config) (*ServerInfo, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	res, raw, err := serverGet(ctx, cfg, "/api/version")
+	if err != nil {
+		diags.Append(diag.WithPath(path.Root("base_url"), apiDiagnostic(diag.SeverityError, "Provider credentials check", err, nil)))
+		return nil, diags
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		diags.Append(credentialsDiagnostic(cfg, res.StatusCode, checkResponse(res, raw)))
+		return nil, diags
+	}
+	if info := parseServerVersion(ctx, res, raw); info != nil {
+		tflog.Debug(ctx, "GoRules API check passed", map[string]interface{}{
+			"server_version":  info.Version,
+			"server_features": strings.Join(featureList(info), ","),
+		})
+		return info, diags
+	}
+
+	// the version stays unknown; the project list decides whether the
+	// credentials and base_url are right
+	res, raw, err = serverGet(ctx, cfg, "/api/projects")
+	if err != nil {
+		diags.Append(diag.WithPath(path.Root("base_url"), apiDiagnostic(diag.SeverityError, "Provider credentials check", err, nil)))
+		return nil, diags
+	}
+	if err := checkResponse(res, raw); err != nil {
+		diags.Append(credentialsDiagnostic(cfg, res.StatusCode, err))
+		return nil, diags
+	}
+	if kind := jsonKind(raw); kind != "array" && kind != "object" {
+		got := res.Header.Get("Content-Type")
+		if got == "" || strings.Contains(got, "json") {
+			got = "a JSON " + kind
+		}
+		diags.AddAttributeError(path.Root("base_url"), "base_url is not a GoRules BRMS API",
+			fmt.Sprintf("GET %s/api/projects answered %d with %s instead of a JSON list of projects. "+
+				"Check that base_url is the BRMS API root (e.g. `https://initial.gorules.io`), not a login page or the web UI. "+
+				"Set skip_credentials_validation = true to skip this check.", cfg.BaseURL, res.StatusCode, got))
+		return nil, diags
+	}
+	tflog.Debug(ctx, "GoRules API check passed")
+	return &ServerInfo{}, diags
+}
+
+func serverGet(ctx context.Context, cfg *Config, apiPath string) (*http.Response, []byte, error) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, cfg.BaseURL+apiPath, nil)
+	req.Header.Set("Accept", "application/json")
+	res, err := apiClient(cfg.HTTP).Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	return res, raw, nil
+}
+
+// credentialsDiagnostic reports a failed check on the configured credential
+// source for 401/403 and on base_url otherwise
+func credentialsDiagnostic(cfg *Config, status int, err error) diag.Diagnostic {
+	d := apiDiagnostic(diag.SeverityError, "Provider credentials check", err, nil)
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		if !cfg.Credentials.Equal(path.Empty()) {
+			d = diag.WithPath(cfg.Credentials, d)
+		}
+		return d
+	}
+	return diag.WithPath(path.Root("base_url"), d)
+}
+
+// parseServerVersion reads a GET /api/version answer, or returns nil when it
+// is not one: releases without the endpoint answer 404, and a 403 (e.g. a
+// token limited to some projects), a 5xx or a login page are left for the
+// project list to judge
+func parseServerVersion(ctx context.Context, res *http.Response, raw []byte) *ServerInfo {
+	var v versionResponse
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		tflog.Debug(ctx, "GoRules server does not publish /api/version")
+		return nil
+	case res.StatusCode >= 300:
+		tflog.Warn(ctx, "GoRules server version unknown", map[string]interface{}{"status": res.StatusCode})
+		return nil
+	case jsonKind(raw) != "object" || json.Unmarshal(raw, &v) != nil || v.Version == "":
+		tflog.Warn(ctx, "GoRules server version unknown: unexpected /api/version response")
+		return nil
+	}
+	info := &ServerInfo{Version: v.Version}
+	if v.Features != nil {
+		info.Features = map[string]bool{}
+		for _, f := range v.Features {
+			info.Features[f] = true
+		}
+	}
+	return info
+}
+
+func featureList(info *ServerInfo) []string {
+	out := make([]string, 0, len(info.Features))
+	for f := range info.Features {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/andredelgado-ruiz/terraform-provider-gorules/internal/mockserver"
+)
+
+func testServerConfig(baseURL, token string) *Config {
+	return &Config{
+		BaseURL:     baseURL,
+		HTTP:        &http.Client{Transport: &authTransport{next: http.DefaultTransport, source: staticToken(token)}},
+		Credentials: path.Root("token"),
+	}
+}
+
+func TestCheckServer(t *testing.T) {
+	ctx := context.Background()
+
+	srv := mockserver.New(mockserver.Options{Version: "1.42.0", Features: []string{FeatureSimulate}})
+	defer srv.Close()
+	info, diags := checkServer(ctx, testServerConfig(srv.URL, srv.Token))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if info.Version != "1.42.0" || !info.Supports(FeatureSimulate) || info.Supports("unknown") {
+		t.Errorf("got %+v", info)
+	}
+	// /api/version is authenticated: it is the only call
+	if n := len(srv.Requests()); n != 1 || srv.CountRequests("GET", "/api/version") != 1 {
+		t.Errorf("%d requests, want only GET /api/version", n)
+	}
+
+	// servers without /api/version support everything
+	old := mockserver.New()
+	defer old.Close()
+	info, diags = checkServer(ctx, testServerConfig(old.URL, old.Token))
+	if diags.HasError() || info.Version != "" || !info.Supports(FeatureSimulate) {
+		t.Errorf("got %+v, %v", info, diags)
+	}
+	if old.CountRequests("GET", "/api/projects") != 1 {
+		t.Error("older releases must be checked with the project list")
+	}
+	// a failing or malformed /api/version leaves the version unknown
+	for _, f := range []mockserver.Fault{
+		{PathPrefix: "/api/version", Status: http.StatusNotFound, Body: `<html>Not Found</html>`},
+		{PathPrefix: "/api/version", Status: http.StatusForbidden},
+		{PathPrefix: "/api/version", Status: http.StatusOK, Body: `<html>Sign in</html>`},
+	} {
+		srv.Inject(f)
+		info, diags = checkServer(ctx, testServerConfig(srv.URL, srv.Token))
+		srv.ClearFaults()
+		if diags.HasError() || info.Version != "" || !info.Supports(FeatureSimulate) {
+			t.Errorf("%d %s: got %+v, %v", f.Status, f.Body, info, diags)
+		}
+	}
+	if (*ServerInfo)(nil).Supports(FeatureSimulate) != true {
+		t.Error("a nil ServerInfo must support every feature")
+	}
+}
+
+func TestCheckServerErrors(t *testing.T) {
+	ctx := context.Background()
+	srv := mockserver.New()
+	defer srv.Close()
+	html := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html>Sign in</html>"))
+	}))
+	defer html.Close()
+	execCfg := testServerConfig(srv.URL, "wrong")
+	execCfg.Credentials = path.Root("exec")
+
+	cases := []struct {
+		name    string
+		cfg     *Config
+		attr    string
+		summary string
+		detail  string
+	}{
+		{"bad token", testServerConfig(srv.URL, "wrong"), "token", "Unauthorized", "Request ID: "},
+		{"bad exec token", execCfg, "exec", "Unauthorized", "configured credential source"},
+		{"not a BRMS", testServerConfig(html.URL, "x"), "base_url", "base_url is not a GoRules BRMS API", "answered 200 with text/html"},
+		{"unreachable", testServerConfig("http://127.0.0.1:1", "x"), "base_url", "Provider credentials check", "base_url"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, diags := checkServer(ctx, tc.cfg)
+			if info != nil || len(diags) != 1 {
+				t.Fatalf("got %+v, %v", info, diags)
+			}
+			d := diags[0]
+			if wp, ok := d.(diag.DiagnosticWithPath); !ok || !wp.Path().Equal(path.Root(tc.attr)) {
+				t.Errorf("diagnostic not attributed to %s: %v", tc.attr, d)
+			}
+			if !strings.Contains(d.Summary(), tc.summary) || !strings.Contains(d.Detail(), tc.detail) {
+				t.Errorf("got %q / %q", d.Summary(), d.Detail())
+			}
+		})
+	}
+}
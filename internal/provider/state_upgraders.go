@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// -----------------------------------------------------------------------------
+// Schema versions & state upgrades
+//
+// Bump a resource's schema Version whenever stored state changes shape (types,
+// renames, value formats) and add an upgrader from the previous version here.
+// Prior schemas are frozen copies: never edit them, and keep the v0 fixtures in
+// testdata/upgrade recorded from the release that wrote them.
+//
+//	v0  0.1.x releases
+//	v1  adds timeouts (all) and force_destroy (project)
+// -----------------------------------------------------------------------------
+
+const (
+	projectSchemaVersion     = 1
+	environmentSchemaVersion = 1
+	groupSchemaVersion       = 1
+)
+
+// nullTimeouts is the value of an unset `timeouts` attribute
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+		"create": types.StringType,
+		"read":   types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	})}
+}
+
+// -----------------------------------------------------------------------------
+// gorules_project
+// -----------------------------------------------------------------------------
+
+type projectModelV0 struct {
+	ID             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	Key            types.String `tfsdk:"key"`
+	Protected      types.Bool   `tfsdk:"protected"`
+	CopyContentRef types.String `tfsdk:"copy_content_ref"`
+}
+
+func projectSchemaV0() *rschema.Schema {
+	return &rschema.Schema{
+		Attributes: map[string]rschema.Attribute{
+			"id":               rschema.StringAttribute{Computed: true},
+			"name":             rschema.StringAttribute{Required: true},
+			"key":              rschema.StringAttribute{Required: true},
+			"protected":        rschema.BoolAttribute{Optional: true, Computed: true},
+			"copy_content_ref": rschema.StringAttribute{Optional: true},
+		},
+	}
+}
+
+func (r *projectResource) UpgradeState(context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {PriorSchema: projectSchemaV0(), StateUpgrader: upgradeProjectStateV0},
+	}
+}
+
+func upgradeProjectStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior projectModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, projectModel{
+		ID:             prior.ID,
+		Name:           prior.Name,
+		Key:            prior.Key,
+		Protected:      prior.Protected,
+		CopyContentRef: prior.CopyContentRef,
+		ForceDestroy:   types.BoolValue(false),
+		Timeouts:       nullTimeouts(),
+	})...)
+}
+
+// -----------------------------------------------------------------------------
+// gorules_environment
+// -----------------------------------------------------------------------------
+
+type environmentModelV0 struct {
+	ID             types.String   `tfsdk:"id"`
+	ProjectID      types.String   `tfsdk:"project_id"`
+	Name           types.String   `tfsdk:"name"`
+	Key            types.String   `tfsdk:"key"`
+	Type           types.String   `tfsdk:"type"`
+	ApprovalMode   types.String   `tfsdk:"approval_mode"`
+	ApprovalGroups []types.String `tfsdk:"approval_groups"`
+}
+
+func environmentSchemaV0() *rschema.Schema {
+	return &rschema.Schema{
+		Attributes: map[string]rschema.Attribute{
+			"id":              rschema.StringAttribute{Computed: true},
+			"project_id":      rschema.StringAttribute{Required: true},
+			"name":            rschema.StringAttribute{Required: true},
+			"key":             rschema.StringAttribute{Optional: true},
+			"type":            rschema.StringAttribute{Required: true},
+			"approval_mode":   rschema.StringAttribute{Optional: true},
+			"approval_groups": rschema.ListAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+		},
+	}
+}
+
+func (r *environmentResource) UpgradeState(context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {PriorSchema: environmentSchemaV0(), StateUpgrader: upgradeEnvironmentStateV0},
+	}
+}
+
+func upgradeEnvironmentStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior environmentModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	groups := prior.ApprovalGroups
+	if groups == nil {
+		groups = EmptyTFStringList() // v1 defaults approval_groups to []
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, environmentModel{
+		ID:             prior.ID,
+		ProjectID:      prior.ProjectID,
+		Name:           prior.Name,
+		Key:            prior.Key,
+		Type:           prior.Type,
+		ApprovalMode:   prior.ApprovalMode,
+		ApprovalGroups: groups,
+		Timeouts:       nullTimeouts(),
+	})...)
+}
+
+// -----------------------------------------------------------------------------
+// gorules_group
+// -----------------------------------------------------------------------------
+
+type groupModelV0 struct {
+	ID          types.String   `tfsdk:"id"`
+	ProjectID   types.String   `tfsdk:"project_id"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	Permissions []types.String `tfsdk:"permissions"`
+}
+
+func groupSchemaV0() *rschema.Schema {
+	return &rschema.Schema{
+		Attributes: map[string]rschema.Attribute{
+			"id":          rschema.StringAttribute{Computed: true},
+			"project_id":  rschema.StringAttribute{Required: true},
+			"name":        rschema.StringAttribute{Required: true},
+			"description": rschema.StringAttribute{Optional: true, Computed: true},
+			"permissions": rschema.ListAttribute{ElementType: types.StringType, Required: true},
+		},
+	}
+}
+
+func (r *groupResource) UpgradeState(context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {PriorSchema: groupSchemaV0(), StateUpgrader: upgradeGroupStateV0},
+	}
+}
+
+func upgradeGroupStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior groupModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, groupModel{
+		ID:          prior.ID,
+		ProjectID:   prior.ProjectID,
+		Name:        prior.Name,
+		Description: prior.Description,
+		Permissions: prior.Permissions,
+		Timeouts:    nullTimeouts(),
+	})...)
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testUpgrade runs the upgrader from version 0 of r on every recorded v0
+// state in testdata/upgrade/<dir> and compares the result with the goldens
+func testUpgrade(t *testing.T, dir string, r resource.ResourceWithUpgradeState) {
+	ctx := context.Background()
+	var current resource.SchemaResponse
+	r.(resource.Resource).Schema(ctx, resource.SchemaRequest{}, &current)
+	if current.Schema.Version != 1 {
+		t.Fatalf("schema version %d: add an upgrader and fixtures for the new version", current.Schema.Version)
+	}
+	upgrader := r.UpgradeState(ctx)[0]
+
+	testGolden(t, "upgrade/"+dir, func(raw []byte) (any, error) {
+		rawState := &tfprotov6.RawState{JSON: raw}
+		prior, err := rawState.Unmarshal(upgrader.PriorSchema.Type().TerraformType(ctx))
+		if err != nil {
+			return nil, err
+		}
+		req := resource.UpgradeStateRequest{
+			RawState: rawState,
+			State:    &tfsdk.State{Schema: upgrader.PriorSchema, Raw: prior},
+		}
+		resp := resource.UpgradeStateResponse{
+			State: tfsdk.State{Schema: current.Schema, Raw: tftypes.NewValue(current.Schema.Type().TerraformType(ctx), nil)},
+		}
+		upgrader.StateUpgrader(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			return nil, errors.New(resp.Diagnostics.Errors()[0].Detail())
+		}
+		return tfValueJSON(resp.State.Raw)
+	})
+}
+
+// tfValueJSON converts a state value into JSON-encodable Go values
+func tfValueJSON(v tftypes.Value) (any, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		err := v.As(&s)
+		return s, err
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		err := v.As(&b)
+		return b, err
+	case v.Type().Is(tftypes.List{}):
+		var elems []tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return nil, err
+		}
+		out := make([]any, 0, len(elems))
+		for _, e := range elems {
+			j, err := tfValueJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, j)
+		}
+		return out, nil
+	case v.Type().Is(tftypes.Object{}):
+		var attrs map[string]tftypes.Value
+		if err := v.As(&attrs); err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(attrs))
+		for name, a := range attrs {
+			j, err := tfValueJSON(a)
+			if err != nil {
+				return nil, err
+			}
+			out[name] = j
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func TestUpgradeProjectState(t *testing.T) {
+	testUpgrade(t, "project", NewProjectResource().(resource.ResourceWithUpgradeState))
+}
+
+func TestUpgradeEnvironmentState(t *testing.T) {
+	testUpgrade(t, "environment", NewEnvironmentResource().(resource.ResourceWithUpgradeState))
+}
+
+func TestUpgradeGroupState(t *testing.T) {
+	testUpgrade(t, "group", NewGroupResource().(resource.ResourceWithUpgradeState))
+}
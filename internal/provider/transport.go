@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// -----------------------------------------------------------------------------
+// HTTP transport settings (timeout, proxy, custom CA, mTLS)
+// -----------------------------------------------------------------------------
+
+const defaultTimeoutSeconds = 30
+
+// transportModel holds the provider attributes that shape Config.HTTP
+type transportModel struct {
+	Timeout            types.Int64  `tfsdk:"timeout"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ClientCertPEM      types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM       types.String `tfsdk:"client_key_pem"`
+	ClientCertFile     types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile      types.String `tfsdk:"client_key_file"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+}
+
+// buildTransport returns the base transport and the per-request timeout
+func buildTransport(m transportModel) (*http.Transport, time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	timeout := time.Duration(defaultTimeoutSeconds) * time.Second
+	if !m.Timeout.IsNull() && !m.Timeout.IsUnknown() {
+		if m.Timeout.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root("timeout"), "Invalid timeout",
+				"timeout must be 0 (no timeout) or a number of seconds.")
+		}
+		timeout = time.Duration(m.Timeout.ValueInt64()) * time.Second
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	// custom CA, added to the system pool
+	caPEM, caAttr := []byte(nil), path.Root("ca_cert_pem")
+	switch {
+	case stringSet(m.CACertPEM) && stringSet(m.CACertFile):
+		diags.AddAttributeError(path.Root("ca_cert_file"), "Conflicting CA settings",
+			"Set either ca_cert_pem or ca_cert_file, not both.")
+	case stringSet(m.CACertPEM):
+		caPEM = []byte(m.CACertPEM.ValueString())
+	case stringSet(m.CACertFile):
+		caAttr = path.Root("ca_cert_file")
+		b, err := os.ReadFile(m.CACertFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(caAttr, "Cannot read CA certificate", err.Error())
+		}
+		caPEM = b
+	}
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			diags.AddAttributeError(caAttr, "Invalid CA certificate",
+				"No PEM-encoded certificate could be parsed.")
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if m.InsecureSkipVerify.ValueBool() {
+		tlsCfg.InsecureSkipVerify = true // #nosec G402 -- explicit opt-in for test instances
+	}
+
+	// client certificate (mTLS)
+	certPEM, keyPEM := []byte(nil), []byte(nil)
+	if stringSet(m.ClientCertPEM) || stringSet(m.ClientKeyPEM) {
+		certPEM, keyPEM = []byte(m.ClientCertPEM.ValueString()), []byte(m.ClientKeyPEM.ValueString())
+	}
+	if stringSet(m.ClientCertFile) || stringSet(m.ClientKeyFile) {
+		if certPEM != nil {
+			diags.AddAttributeError(path.Root("client_cert_file"), "Conflicting client certificate settings",
+				"Set either client_cert_pem/client_key_pem or client_cert_file/client_key_file, not both.")
+		}
+		var err error
+		if certPEM, err = os.ReadFile(m.ClientCertFile.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("client_cert_file"), "Cannot read client certificate", err.Error())
+		}
+		if keyPEM, err = os.ReadFile(m.ClientKeyFile.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("client_key_file"), "Cannot read client key", err.Error())
+		}
+	}
+	if certPEM != nil && !diags.HasError() {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			diags.AddAttributeError(path.Root("client_cert_pem"), "Invalid client certificate or key",
+				"The certificate and key must be a matching PEM-encoded pair: "+err.Error())
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = tlsCfg
+
+	// explicit proxy; otherwise HTTP_PROXY/HTTPS_PROXY/NO_PROXY apply
+	if stringSet(m.ProxyURL) {
+		u, err := url.Parse(m.ProxyURL.ValueString())
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			diags.AddAttributeError(path.Root("proxy_url"), "Invalid proxy URL",
+				"proxy_url must be an absolute URL such as `http://proxy.example.com:3128`.")
+		} else {
+			t.Proxy = http.ProxyURL(u)
+		}
+	}
+
+	return t, timeout, diags
+}
+
+func stringSet(v types.String) bool {
+	return !v.IsNull() && !v.IsUnknown() && v.ValueString() != ""
+}
+
+// -----------------------------------------------------------------------------
+// Request headers (User-Agent, extra_headers)
+// -----------------------------------------------------------------------------
+
+// headers the provider manages itself; extra_headers cannot override them
+var reservedHeaders = map[string]bool{
+	"Authorization":  true,
+	"Content-Type":   true,
+	"Content-Length": true,
+	"Accept":         true,
+	"Host":           true,
+	"User-Agent":     true,
+}
+
+// userAgent identifies Terraform traffic in the BRMS access logs
+func userAgent(providerVersion, terraformVersion string) string {
+	ua := "terraform-provider-gorules/" + providerVersion
+	if terraformVersion != "" {
+		ua += " terraform/" + terraformVersion
+	}
+	return ua
+}
+
+type headerTransport struct {
+	next      http.RoundTripper
+	userAgent string
+	extra     map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context()) // RoundTrippers must not modify the caller's request
+	for k, v := range t.extra {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
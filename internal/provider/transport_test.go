@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testClient(t *testing.T, m transportModel) *http.Client {
+	t.Helper()
+	transport, timeout, diags := buildTransport(m)
+	if diags.HasError() {
+		t.Fatalf("buildTransport: %v", diags)
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+func testGet(c *http.Client, url string) error {
+	res, err := c.Get(url)
+	if err == nil {
+		res.Body.Close()
+	}
+	return err
+}
+
+// testClientCert returns a self-signed client certificate and key as PEM
+func testClientCert(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestBuildTransportCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	if err := testGet(testClient(t, transportModel{}), srv.URL); err == nil {
+		t.Fatal("expected an unknown authority error without ca_cert_pem")
+	}
+	if err := testGet(testClient(t, transportModel{CACertPEM: types.StringValue(string(caPEM))}), srv.URL); err != nil {
+		t.Fatalf("ca_cert_pem: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(file, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := testGet(testClient(t, transportModel{CACertFile: types.StringValue(file)}), srv.URL); err != nil {
+		t.Fatalf("ca_cert_file: %v", err)
+	}
+	if err := testGet(testClient(t, transportModel{InsecureSkipVerify: types.BoolValue(true)}), srv.URL); err != nil {
+		t.Fatalf("insecure_skip_verify: %v", err)
+	}
+}
+
+func TestBuildTransportClientCertificate(t *testing.T) {
+	certPEM, keyPEM := testClientCert(t)
+	block, _ := pem.Decode(certPEM)
+	cert, _ := x509.ParseCertificate(block.Bytes)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	insecure := types.BoolValue(true)
+	if err := testGet(testClient(t, transportModel{InsecureSkipVerify: insecure}), srv.URL); err == nil {
+		t.Fatal("expected the server to require a client certificate")
+	}
+	m := transportModel{
+		InsecureSkipVerify: insecure,
+		ClientCertPEM:      types.StringValue(string(certPEM)),
+		ClientKeyPEM:       types.StringValue(string(keyPEM)),
+	}
+	if err := testGet(testClient(t, m), srv.URL); err != nil {
+		t.Fatalf("client certificate: %v", err)
+	}
+}
+
+func TestBuildTransportProxyAndTimeout(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String() // absolute URL when used as a proxy
+	}))
+	defer proxy.Close()
+
+	c := testClient(t, transportModel{ProxyURL: types.StringValue(proxy.URL)})
+	if err := testGet(c, "http://gorules.invalid/api/projects"); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != "http://gorules.invalid/api/projects" {
+		t.Fatalf("request not sent through the proxy: %q", proxied)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { time.Sleep(2 * time.Second) }))
+	defer slow.Close()
+	if err := testGet(testClient(t, transportModel{Timeout: types.Int64Value(1)}), slow.URL); err == nil {
+		t.Fatal("expected a timeout")
+	}
+}
+
+func TestBuildTransportInvalidSettings(t *testing.T) {
+	for name, m := range map[string]transportModel{
+		"both CAs":    {CACertPEM: types.StringValue("x"), CACertFile: types.StringValue("y")},
+		"bad CA":      {CACertPEM: types.StringValue("not a certificate")},
+		"missing key": {ClientCertPEM: types.StringValue("not a certificate")},
+		"bad proxy":   {ProxyURL: types.StringValue("proxy:3128")},
+		"negative":    {Timeout: types.Int64Value(-1)},
+	} {
+		if _, _, diags := buildTransport(m); !diags.HasError() {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
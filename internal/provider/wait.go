@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Operation timeouts & retries
+//
+// CRUD operations run under the deadline of the resource's `timeouts` block;
+// retries wait with sleepCtx so they stop as soon as the deadline passes.
+// -----------------------------------------------------------------------------
+
+const (
+	defaultTimeout       = 5 * time.Minute
+	defaultDeleteTimeout = 10 * time.Minute // force_destroy may delete many children
+
+	retryMinDelay = 400 * time.Millisecond
+	retryMaxDelay = 10 * time.Second
+)
+
+// backoff returns the delay before retry n (0-based), doubling up to retryMaxDelay
+func backoff(n int) time.Duration {
+	d := retryMinDelay
+	for i := 0; i < n && d < retryMaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, retryMaxDelay)
+}
+
+// sleepCtx waits d, or returns ctx.Err() if the context ends first
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryable reports whether a failed API call may succeed if repeated:
+// 5xx, 429 and network errors, but not rejected credentials or a passed deadline
+func retryable(err error) bool {
+	var apiErr *apiError
+	var credErr *credentialError
+	var urlErr *url.Error
+	switch {
+	case err == nil, errors.As(err, &credErr), errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return false
+	case errors.As(err, &apiErr):
+		return apiErr.Status >= 500 || apiErr.Status == http.StatusTooManyRequests
+	case errors.As(err, &urlErr):
+		return true
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// Consistency waiter
+//
+// Environment and group listings are eventually consistent: right after a
+// create or update the listing may miss the object or still show old values.
+// Create/Update wait until the listing agrees, so Read can trust a miss.
+// -----------------------------------------------------------------------------
+
+// waitForListing polls listed with backoff until it reports the object with
+// the expected values, or ctx (the operation timeout) ends. Retryable API
+// errors are retried too.
+func waitForListing(ctx context.Context, what string, listed func(context.Context) (bool, error)) error {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		ok, err := listed(ctx)
+		if err == nil && ok {
+			return nil
+		}
+		if err != nil && !retryable(err) {
+			return err
+		}
+		lastErr = err
+		if sleepCtx(ctx, backoff(attempt)) != nil {
+			if lastErr != nil {
+				return fmt.Errorf("%s was not listed with the expected values before the timeout: %w", what, lastErr)
+			}
+			return fmt.Errorf("%s was not listed with the expected values before the timeout", what)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{400 * time.Millisecond, 800 * time.Millisecond, 1600 * time.Millisecond}
+	for n, d := range want {
+		if got := backoff(n); got != d {
+			t.Errorf("backoff(%d) = %s, want %s", n, got, d)
+		}
+	}
+	if got := backoff(100); got != retryMaxDelay {
+		t.Errorf("backoff(100) = %s, want %s", got, retryMaxDelay)
+	}
+}
+
+func TestSleepCtx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := sleepCtx(ctx, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("sleepCtx did not stop at the deadline")
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&apiError{Status: http.StatusServiceUnavailable}, true},
+		{fmt.Errorf("listing: %w", &apiError{Status: http.StatusTooManyRequests}), true},
+		{&apiError{Status: http.StatusConflict}, false},
+		{&url.Error{Op: "Get", URL: "http://x", Err: errors.New("connection refused")}, true},
+		{&url.Error{Op: "Get", URL: "http://x", Err: &credentialError{"token_file", errors.New("missing")}}, false},
+		{&url.Error{Op: "Get", URL: "http://x", Err: context.DeadlineExceeded}, false},
+		{nil, false},
+	}
+	for _, tc := range cases {
+		if got := retryable(tc.err); got != tc.want {
+			t.Errorf("retryable(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}